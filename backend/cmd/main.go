@@ -1,92 +1,94 @@
 package main
 
 import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
-	"net/http"
 	"notes-api/internal/database"
-	"notes-api/internal/handlers"
-	"notes-api/internal/middleware"
+	"notes-api/internal/store"
+	"notes-api/internal/store/sqlstore"
 	"os"
 
-	"github.com/go-chi/chi/v5"
-	chimiddleware "github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 )
 
+const usage = `Pemakaian: notes-api [perintah]
+
+  serve             jalankan HTTP server (default jika tanpa perintah)
+  migrate           kelola migration database (up, down, status, baseline)
+  user              kelola akun user (create, list, disable, enable, reset-password)
+  export-user       export semua data satu user ke file JSON
+  import-user       import user dari file hasil export-user
+  purge-trash       hapus permanen isi trash yang sudah melewati masa simpan
+
+Semua perintah memakai konfigurasi yang sama dengan server (.env / environment).
+Jalankan "notes-api <perintah> -h" untuk detail tiap perintah.`
+
 func main() {
 	// Load environment variables dari .env file
 	godotenv.Load()
 
-	// Connect ke database
-	if err := database.Connect(); err != nil {
-		log.Fatal("Gagal koneksi database:", err)
+	// Tanpa argumen server langsung jalan, sama seperti start command di Railway
+	command, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
 	}
-	defer database.Close()
-
-	// Inisialisasi Chi router
-	r := chi.NewRouter()
 
-	// Middleware
-	r.Use(chimiddleware.Logger)    // Log setiap request
-	r.Use(chimiddleware.Recoverer) // Recover dari panic
-
-	// CORS middleware
-	frontendURL := os.Getenv("FRONTEND_URL")
-	if frontendURL == "" {
-		frontendURL = "http://localhost:5173"
+	var err error
+	switch command {
+	case "serve":
+		err = runServe(args)
+	case "migrate":
+		err = runMigrate(args)
+	case "user":
+		err = runUser(args)
+	case "export-user":
+		err = runExportUser(args)
+	case "import-user":
+		err = runImportUser(args)
+	case "purge-trash":
+		err = runPurgeTrash(args)
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+	default:
+		err = errors.New(usage)
 	}
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{frontendURL, "http://localhost:5173", "*"}, // * untuk development
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		AllowCredentials: false, // Set false untuk wildcard origin
-	}))
-
-	// Routes tanpa auth
-	r.Post("/api/register", handlers.Register)
-	r.Post("/api/login", handlers.Login)
-
-	// Routes dengan auth (protected)
-	r.Group(func(r chi.Router) {
-		r.Use(middleware.Auth) // Semua route di grup ini butuh JWT token
 
-		// Folders
-		r.Get("/api/folders", handlers.GetFolders)
-		r.Post("/api/folders", handlers.CreateFolder)
-		r.Put("/api/folders/{id}", handlers.UpdateFolder)
-		r.Delete("/api/folders/{id}", handlers.DeleteFolder)
-
-		// Notes
-		r.Get("/api/notes", handlers.GetNotes)
-		r.Get("/api/notes/{id}", handlers.GetNoteByID)
-		r.Get("/api/folders/{id}/notes", handlers.GetNotesByFolder)
-		r.Get("/api/tags/{id}/notes", handlers.GetNotesByTag)
-		r.Post("/api/notes", handlers.CreateNote)
-		r.Put("/api/notes/{id}", handlers.UpdateNote)
-		r.Delete("/api/notes/{id}", handlers.DeleteNote)
-
-		// Tags
-		r.Get("/api/tags", handlers.GetTags)
-		r.Post("/api/tags", handlers.CreateTag)
-		r.Delete("/api/tags/{id}", handlers.DeleteTag)
+	// -h pada subcommand sudah menampilkan usage-nya, bukan error
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		log.Fatal(err)
+	}
+}
 
-		// Tag assignment
-		r.Post("/api/notes/{noteId}/tags/{tagId}", handlers.AssignTagToNote)
-		r.Delete("/api/notes/{noteId}/tags/{tagId}", handlers.RemoveTagFromNote)
-	})
+// openStore membuka koneksi database dari environment, menjalankan migration yang belum
+// diterapkan (kecuali AUTO_MIGRATE=false), lalu menyiapkan repository. Dipakai server dan
+// semua perintah admin supaya semuanya membaca konfigurasi dan data dengan cara yang sama.
+func openStore() (*store.Store, *sql.DB, error) {
+	db, err := database.Connect()
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal koneksi database: %w", err)
+	}
 
-	// Health check
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("Notes API is running!"))
-	})
+	if database.AutoMigrate() {
+		if err := migrateUp(db); err != nil {
+			database.Close(db)
+			return nil, nil, fmt.Errorf("gagal menjalankan migration: %w", err)
+		}
+	}
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	st, err := sqlstore.New(db, database.Driver())
+	if err != nil {
+		database.Close(db)
+		return nil, nil, fmt.Errorf("gagal menyiapkan repository: %w", err)
 	}
+	return st, db, nil
+}
 
-	log.Printf("Server berjalan di http://localhost:%s\n", port)
-	log.Fatal(http.ListenAndServe(":"+port, r))
+// newFlagSet membuat FlagSet untuk subcommand; -h atau flag yang salah menampilkan usage
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprintln(fs.Output(), usage) }
+	return fs
 }
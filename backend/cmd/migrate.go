@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"notes-api/internal/database"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+const migrateUsage = `Pemakaian: notes-api migrate <perintah>
+
+  up                jalankan semua migration yang belum diterapkan
+  down [N]          rollback N migration terakhir (default 1)
+  status            tampilkan status setiap migration
+  baseline VERSI    tandai migration sampai VERSI sudah diterapkan tanpa menjalankannya
+                    (untuk database yang skemanya dibuat manual)`
+
+// runMigrate menjalankan subcommand migrate
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	migrator, err := database.NewMigrator(db, database.Driver())
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return migrateUp(db)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errors.New("jumlah migration untuk down harus angka positif")
+			}
+		}
+		done, err := migrator.Down(ctx, steps)
+		for _, mig := range done {
+			log.Printf("Migration %s di-rollback\n", mig.Name)
+		}
+		return err
+
+	case "status":
+		list, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSI\tMIGRATION\tSTATUS\tDITERAPKAN")
+		for _, s := range list {
+			status, appliedAt := "belum", "-"
+			if s.AppliedAt != nil {
+				status, appliedAt = "diterapkan", s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Changed {
+				status = "DIEDIT"
+			}
+			if s.Missing {
+				status = "FILE HILANG"
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+		}
+		return w.Flush()
+
+	case "baseline":
+		if len(args) < 2 {
+			return errors.New("versi baseline wajib diisi, misalnya: migrate baseline 15")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return errors.New("versi baseline harus angka")
+		}
+		done, err := migrator.Baseline(ctx, version)
+		for _, mig := range done {
+			log.Printf("Migration %s ditandai sudah diterapkan\n", mig.Name)
+		}
+		return err
+
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// migrateUp menjalankan migration yang belum diterapkan, dipakai saat server start
+// (AUTO_MIGRATE) dan oleh `migrate up`
+func migrateUp(db *sql.DB) error {
+	migrator, err := database.NewMigrator(db, database.Driver())
+	if err != nil {
+		return err
+	}
+
+	done, err := migrator.Up(context.Background())
+	for _, mig := range done {
+		log.Printf("Migration %s diterapkan\n", mig.Name)
+	}
+	if err == nil && len(done) == 0 {
+		log.Println("Skema database sudah versi terbaru")
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+// mock-oidc menjalankan identity provider OIDC palsu untuk mencoba login SSO secara lokal.
+//
+//	go run ./cmd/mock-oidc -addr :9000 -client-id notes-local
+//
+// Lalu set OIDC_ISSUER=http://localhost:9000 dan OIDC_CLIENT_ID=notes-local di .env API.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"notes-api/internal/oidc/mock"
+	"strings"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "alamat listen")
+	issuer := flag.String("issuer", "", "issuer URL (default http://localhost<addr>)")
+	clientID := flag.String("client-id", "notes-local", "client_id yang diterima")
+	email := flag.String("email", "mock@example.com", "email user default (bisa diganti per login dengan login_hint)")
+	flag.Parse()
+
+	if *issuer == "" {
+		host := *addr
+		if strings.HasPrefix(host, ":") {
+			host = "localhost" + host
+		}
+		*issuer = "http://" + host
+	}
+
+	provider, err := mock.New(*issuer, *clientID)
+	if err != nil {
+		log.Fatal("Gagal membuat mock provider:", err)
+	}
+	provider.Claims["email"] = *email
+
+	log.Printf("Mock OIDC provider berjalan di %s (client_id %s)\n", *issuer, *clientID)
+	log.Fatal(http.ListenAndServe(*addr, provider))
+}
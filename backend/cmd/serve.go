@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"notes-api/internal/database"
+	"notes-api/internal/handlers"
+	"notes-api/internal/mail"
+	"notes-api/internal/oidc"
+	"notes-api/internal/ratelimit"
+	"notes-api/internal/trash"
+	"notes-api/internal/utils"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+)
+
+const serveUsage = `Pemakaian: notes-api serve
+
+Menjalankan HTTP server di port dari env PORT (default 8080). Ini perintah default
+jika notes-api dijalankan tanpa argumen.`
+
+// runServe menjalankan HTTP server sampai proses dihentikan
+func runServe(args []string) error {
+	if len(args) > 0 {
+		return errors.New(serveUsage)
+	}
+
+	// Muat kunci JWT, server tidak boleh jalan tanpa kunci yang layak
+	if err := utils.LoadKeys(); err != nil {
+		return fmt.Errorf("gagal memuat kunci JWT: %w", err)
+	}
+
+	// Semua akses data lewat repository, handler tidak tahu database apa yang dipakai
+	st, db, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+	h := handlers.New(st)
+
+	// Pilih cara pengiriman email (smtp, file, atau log)
+	if h.Mailer, err = mail.FromEnv(); err != nil {
+		return fmt.Errorf("gagal menyiapkan mailer: %w", err)
+	}
+
+	// Pilih penyimpanan rate limit login (memory atau database)
+	loginAttempts, err := ratelimit.StoreFromEnv(st.LoginAttempts)
+	if err != nil {
+		return fmt.Errorf("gagal menyiapkan rate limit: %w", err)
+	}
+	h.LoginLimit = ratelimit.NewLoginLimiter(loginAttempts)
+
+	// Login SSO (OIDC) aktif jika OIDC_ISSUER diset
+	if h.OIDC, err = oidc.FromEnv(); err != nil {
+		return fmt.Errorf("gagal menyiapkan OIDC: %w", err)
+	}
+
+	// Hapus permanen isi trash yang sudah melewati masa simpan (dicek setiap jam)
+	trash.StartPurger(st.Trash, trash.RetentionDays(), time.Hour)
+
+	// Inisialisasi Chi router
+	r := chi.NewRouter()
+
+	// Middleware
+	r.Use(chimiddleware.Logger)    // Log setiap request
+	r.Use(chimiddleware.Recoverer) // Recover dari panic
+
+	// CORS middleware
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+	r.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   []string{frontendURL, "http://localhost:5173"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"ETag", "Retry-After"},
+		AllowCredentials: true, // Cookie state login SSO, origin tidak boleh wildcard
+	}))
+
+	// Semua endpoint API, lihat handlers.Routes
+	h.Routes(r)
+
+	// Health check
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Notes API is running!"))
+	})
+
+	// Start server
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	log.Printf("Server berjalan di http://localhost:%s\n", port)
+	return http.ListenAndServe(":"+port, r)
+}
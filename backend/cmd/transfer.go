@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"notes-api/internal/database"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"os"
+)
+
+const exportUserUsage = `Pemakaian: notes-api export-user [-o FILE] USER
+
+Menulis akun beserta semua folder, tag, catatan (termasuk trash) dan revisinya ke
+file JSON, atau ke stdout tanpa -o. USER adalah ID atau email akun. File berisi
+hash password, simpan dengan aman.`
+
+const importUserUsage = `Pemakaian: notes-api import-user [-username U] [-email E] FILE
+
+Membuat user baru dari file hasil export-user (FILE "-" untuk stdin). Semua data
+mendapat ID baru. -username dan -email mengganti nilai di file, misalnya jika
+sudah dipakai akun lain.`
+
+// runExportUser menjalankan subcommand export-user
+func runExportUser(args []string) error {
+	fs := newFlagSet("export-user", exportUserUsage)
+	output := fs.String("o", "", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New(exportUserUsage)
+	}
+
+	st, db, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	ctx := context.Background()
+	user, err := findUser(ctx, st.Users, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	data, err := st.Transfer.Export(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("gagal export user: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		// Hanya pemilik file yang boleh membaca karena isinya termasuk hash password
+		f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return fmt.Errorf("gagal menulis file export: %w", err)
+	}
+
+	// Ringkasan ke stderr supaya tidak tercampur dengan JSON di stdout
+	fmt.Fprintf(os.Stderr, "User %s di-export: %d folder, %d tag, %d catatan\n", user.Username, len(data.Folders), len(data.Tags), len(data.Notes))
+	return nil
+}
+
+// runImportUser menjalankan subcommand import-user
+func runImportUser(args []string) error {
+	fs := newFlagSet("import-user", importUserUsage)
+	username := fs.String("username", "", "")
+	email := fs.String("email", "", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New(importUserUsage)
+	}
+
+	var r io.Reader = os.Stdin
+	if fs.Arg(0) != "-" {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var data models.UserExport
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("file export tidak bisa dibaca: %w", err)
+	}
+	if *username != "" {
+		data.User.Username = *username
+	}
+	if *email != "" {
+		data.User.Email = *email
+	}
+
+	// Validasi dulu supaya file yang rusak tidak perlu membuka koneksi database
+	if err := store.CheckExport(data); err != nil {
+		return err
+	}
+
+	st, db, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	userID, err := st.Transfer.Import(context.Background(), data)
+	if errors.Is(err, store.ErrDuplicate) {
+		return fmt.Errorf("username %q atau email %q sudah digunakan, ganti dengan -username atau -email", data.User.Username, data.User.Email)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal import user: %w", err)
+	}
+
+	fmt.Printf("User %s di-import dengan ID %d: %d folder, %d tag, %d catatan\n", data.User.Username, userID, len(data.Folders), len(data.Tags), len(data.Notes))
+	return nil
+}
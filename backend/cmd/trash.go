@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"notes-api/internal/database"
+	"notes-api/internal/trash"
+)
+
+const purgeTrashUsage = `Pemakaian: notes-api purge-trash [-days N]
+
+Menghapus permanen catatan dan folder semua user yang sudah lebih dari N hari di
+trash. Default N dari env TRASH_RETENTION_DAYS (30 hari). Server juga menjalankan
+ini setiap jam; perintah ini untuk membersihkan sekarang tanpa menunggu.`
+
+// runPurgeTrash menjalankan subcommand purge-trash
+func runPurgeTrash(args []string) error {
+	fs := newFlagSet("purge-trash", purgeTrashUsage)
+	days := fs.Int("days", trash.RetentionDays(), "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 || *days < 0 {
+		return errors.New(purgeTrashUsage)
+	}
+
+	st, db, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	n, err := trash.PurgeExpired(context.Background(), st.Trash, *days)
+	if err != nil {
+		return fmt.Errorf("gagal membersihkan trash: %w", err)
+	}
+
+	fmt.Printf("Trash dibersihkan: %d item dihapus permanen\n", n)
+	return nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"notes-api/internal/database"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const userUsage = `Pemakaian: notes-api user <perintah>
+
+  create -username U -email E [-full-name NAMA] [-password P] [-unverified]
+                    buat akun baru (email langsung terverifikasi kecuali -unverified)
+  list              tampilkan semua akun
+  disable USER      nonaktifkan akun dan cabut semua sesinya
+  enable USER       aktifkan kembali akun yang dinonaktifkan
+  reset-password [-password P] USER
+                    ganti password lalu cabut semua sesi akun
+
+USER adalah ID atau email akun. Tanpa -password dibuatkan password acak yang
+ditampilkan sekali di output.`
+
+// runUser menjalankan subcommand user
+func runUser(args []string) error {
+	if len(args) == 0 {
+		return errors.New(userUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		return userCreate(args[1:])
+	case "list":
+		return userList(args[1:])
+	case "disable":
+		return userSetDisabled(args[1:], true)
+	case "enable":
+		return userSetDisabled(args[1:], false)
+	case "reset-password":
+		return userResetPassword(args[1:])
+	default:
+		return errors.New(userUsage)
+	}
+}
+
+func userCreate(args []string) error {
+	fs := newFlagSet("user create", userUsage)
+	username := fs.String("username", "", "")
+	email := fs.String("email", "", "")
+	fullName := fs.String("full-name", "", "")
+	password := fs.String("password", "", "")
+	unverified := fs.Bool("unverified", false, "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*username) == "" || strings.TrimSpace(*email) == "" || fs.NArg() > 0 {
+		return errors.New("username dan email wajib diisi, contoh: notes-api user create -username budi -email budi@example.com")
+	}
+
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = randomPassword(); err != nil {
+			return err
+		}
+	}
+	hash, err := utils.HashPassword(*password)
+	if err != nil {
+		return fmt.Errorf("gagal memproses password: %w", err)
+	}
+
+	st, db, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	// Akun yang dibuat admin dianggap sudah memverifikasi email-nya
+	user := models.User{Username: *username, Email: *email, PasswordHash: hash, FullName: *fullName}
+	if !*unverified {
+		verifiedAt := time.Now().UTC()
+		user.EmailVerifiedAt = &verifiedAt
+	}
+
+	err = st.Users.Create(context.Background(), &user)
+	if errors.Is(err, store.ErrDuplicate) {
+		return errors.New("username atau email sudah digunakan")
+	}
+	if err != nil {
+		return fmt.Errorf("gagal membuat user: %w", err)
+	}
+
+	fmt.Printf("User %s dibuat dengan ID %d\n", user.Username, user.ID)
+	if generated {
+		fmt.Printf("Password: %s\n", *password)
+	}
+	return nil
+}
+
+func userList(args []string) error {
+	if len(args) > 0 {
+		return errors.New(userUsage)
+	}
+
+	st, db, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	users, err := st.Users.List(context.Background())
+	if err != nil {
+		return fmt.Errorf("gagal mengambil daftar user: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tVERIFIKASI\t2FA\tSTATUS\tDIBUAT")
+	for _, u := range users {
+		verified, twoFactor, status := "belum", "-", "aktif"
+		if u.EmailVerifiedAt != nil {
+			verified = "ya"
+		}
+		if u.TwoFactorEnabled {
+			twoFactor = "aktif"
+		}
+		if u.DisabledAt != nil {
+			status = "nonaktif sejak " + u.DisabledAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", u.ID, u.Username, u.Email, verified, twoFactor, status, u.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func userSetDisabled(args []string, disabled bool) error {
+	if len(args) != 1 {
+		return errors.New(userUsage)
+	}
+
+	st, db, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	ctx := context.Background()
+	user, err := findUser(ctx, st.Users, args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := st.Users.SetDisabled(ctx, user.ID, disabled); err != nil {
+		return fmt.Errorf("gagal mengubah status user: %w", err)
+	}
+	if !disabled {
+		fmt.Printf("User %s (ID %d) diaktifkan kembali\n", user.Username, user.ID)
+		return nil
+	}
+
+	// Sesi yang sedang berjalan ikut berhenti, bukan menunggu access token kedaluwarsa
+	if err := st.Tokens.RevokeAllForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("user dinonaktifkan tapi gagal mencabut sesinya: %w", err)
+	}
+	fmt.Printf("User %s (ID %d) dinonaktifkan, semua sesinya dicabut\n", user.Username, user.ID)
+	return nil
+}
+
+func userResetPassword(args []string) error {
+	fs := newFlagSet("user reset-password", userUsage)
+	password := fs.String("password", "", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New(userUsage)
+	}
+
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = randomPassword(); err != nil {
+			return err
+		}
+	}
+	hash, err := utils.HashPassword(*password)
+	if err != nil {
+		return fmt.Errorf("gagal memproses password: %w", err)
+	}
+
+	st, db, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	ctx := context.Background()
+	user, err := findUser(ctx, st.Users, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if err := st.Users.SetPassword(ctx, user.ID, hash); err != nil {
+		return fmt.Errorf("gagal mengganti password: %w", err)
+	}
+
+	// Sama seperti reset lewat email: sesi lama tidak berlaku lagi
+	if err := st.Tokens.RevokeAllForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("password diganti tapi gagal mencabut sesi lama: %w", err)
+	}
+
+	fmt.Printf("Password user %s (ID %d) diganti, semua sesinya dicabut\n", user.Username, user.ID)
+	if generated {
+		fmt.Printf("Password: %s\n", *password)
+	}
+	return nil
+}
+
+// findUser mencari user berdasarkan ID atau email
+func findUser(ctx context.Context, users store.UserStore, ref string) (models.User, error) {
+	var user models.User
+	var err error
+	if id, convErr := strconv.Atoi(ref); convErr == nil {
+		user, err = users.Get(ctx, id)
+	} else {
+		user, err = users.GetByEmail(ctx, ref)
+	}
+
+	if errors.Is(err, store.ErrNotFound) {
+		return user, fmt.Errorf("user %q tidak ditemukan", ref)
+	}
+	return user, err
+}
+
+// randomPassword membuat password acak 16 karakter untuk akun yang dibuat atau di-reset admin
+func randomPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
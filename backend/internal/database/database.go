@@ -5,14 +5,42 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
-var DB *sql.DB
+// Database yang didukung (env DB_DRIVER)
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+)
+
+// Driver membaca env DB_DRIVER, default mysql
+func Driver() string {
+	if driver := strings.ToLower(os.Getenv("DB_DRIVER")); driver != "" {
+		return driver
+	}
+	return MySQL
+}
+
+// Connect membuat koneksi ke database sesuai DB_DRIVER
+func Connect() (*sql.DB, error) {
+	switch driver := Driver(); driver {
+	case MySQL:
+		return connectMySQL()
+	case Postgres:
+		return connectPostgres()
+	case SQLite:
+		return connectSQLite()
+	default:
+		return nil, fmt.Errorf("DB_DRIVER %q tidak dikenal, pilih mysql, postgres atau sqlite", driver)
+	}
+}
 
-// Connect membuat koneksi ke MySQL database
-func Connect() error {
+// connectMySQL membuat koneksi ke MySQL database
+func connectMySQL() (*sql.DB, error) {
 	// Baca konfigurasi dari environment variables
 	dbUser := os.Getenv("DB_USER")
 	dbPassword := os.Getenv("DB_PASSWORD")
@@ -25,25 +53,25 @@ func Connect() error {
 		dbUser, dbPassword, dbHost, dbPort, dbName)
 
 	// Buka koneksi ke database
-	var err error
-	DB, err = sql.Open("mysql", dsn)
+	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		return fmt.Errorf("error membuka koneksi database: %v", err)
+		return nil, fmt.Errorf("error membuka koneksi database: %v", err)
 	}
 
 	// Test koneksi
-	if err = DB.Ping(); err != nil {
-		return fmt.Errorf("error ping database: %v", err)
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error ping database: %v", err)
 	}
 
 	log.Println("✅ Koneksi database MySQL berhasil")
-	return nil
+	return db, nil
 }
 
 // Close menutup koneksi database
-func Close() {
-	if DB != nil {
-		DB.Close()
+func Close(db *sql.DB) {
+	if db != nil {
+		db.Close()
 		log.Println("Koneksi database ditutup")
 	}
 }
@@ -0,0 +1,576 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"notes-api/migrations"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Baris pemisah bagian up dan down di file migration
+const downMarker = "-- migrate:down"
+
+// Nama lock MySQL (GET_LOCK) dan key advisory lock Postgres yang dipegang selama migration
+const (
+	mysqlMigrationLock    = "notes_api_migrate"
+	postgresMigrationLock = 7251946301
+)
+
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`
+
+// Migration adalah satu file migration yang di-embed di binary
+type Migration struct {
+	Version  int
+	Name     string // nama file, misalnya 001_create_tables.sql
+	Up       string
+	Down     string // kosong jika migration tidak bisa di-rollback
+	Checksum string // SHA-256 isi file
+}
+
+// MigrationStatus adalah status satu migration di database
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time // nil jika belum diterapkan
+	Changed   bool       // file sudah diedit setelah diterapkan (checksum berbeda)
+	Missing   bool       // tercatat diterapkan tapi file-nya tidak ada di binary
+}
+
+// appliedMigration adalah satu baris tabel schema_migrations
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// execer dipenuhi oleh *sql.Conn dan *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// migrationDialect berisi perbedaan cara menjalankan migration antar database
+type migrationDialect struct {
+	insert string // mencatat migration ke schema_migrations
+	delete string // menghapus catatan migration saat rollback
+
+	// lock dipegang selama migration supaya beberapa instance tidak menjalankannya
+	// bersamaan; unlock dipanggil dengan failed=true jika migration gagal
+	lock   func(ctx context.Context, conn *sql.Conn) error
+	unlock func(ctx context.Context, conn *sql.Conn, failed bool) error
+
+	// transactional: setiap migration dijalankan dalam transaksi sendiri
+	transactional bool
+
+	// singleTransaction: lock adalah transaksi yang membungkus semua migration, jadi
+	// tidak ada yang tersimpan jika salah satu gagal
+	singleTransaction bool
+
+	// splitStatements: driver hanya bisa menjalankan satu statement per Exec
+	splitStatements bool
+
+	// existing mengembalikan versi terakhir yang sudah diterapkan sebelum ada
+	// schema_migrations (0 jika database masih kosong)
+	existing func(ctx context.Context, conn *sql.Conn) (int, error)
+
+	// undefinedTable mengenali error query ke tabel yang belum ada
+	undefinedTable func(err error) bool
+}
+
+var migrationDialects = map[string]migrationDialect{
+	// DDL MySQL tidak bisa di-rollback, jadi migration tidak dibungkus transaksi
+	MySQL: {
+		insert: "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+		delete: "DELETE FROM schema_migrations WHERE version = ?",
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			var ok sql.NullInt64
+			if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 60)", mysqlMigrationLock).Scan(&ok); err != nil {
+				return err
+			}
+			if ok.Int64 != 1 {
+				return errors.New("timeout menunggu lock migration, instance lain sedang menjalankan migration")
+			}
+			return nil
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn, failed bool) error {
+			var released sql.NullInt64
+			return conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", mysqlMigrationLock).Scan(&released)
+		},
+		splitStatements: true,
+		existing:        requireBaseline(isMySQLUndefinedTable),
+		undefinedTable:  isMySQLUndefinedTable,
+	},
+	Postgres: {
+		insert: "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)",
+		delete: "DELETE FROM schema_migrations WHERE version = $1",
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", postgresMigrationLock)
+			return err
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn, failed bool) error {
+			var unlocked bool
+			return conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", postgresMigrationLock).Scan(&unlocked)
+		},
+		transactional:  true,
+		existing:       requireBaseline(isPostgresUndefinedTable),
+		undefinedTable: isPostgresUndefinedTable,
+	},
+	// Lock SQLite adalah transaksi write (BEGIN IMMEDIATE) yang membungkus semua migration
+	// dalam satu kali jalan: berhasil semua atau tidak sama sekali
+	SQLite: {
+		insert: "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+		delete: "DELETE FROM schema_migrations WHERE version = ?",
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+			return err
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn, failed bool) error {
+			if failed {
+				_, err := conn.ExecContext(ctx, "ROLLBACK")
+				return err
+			}
+			_, err := conn.ExecContext(ctx, "COMMIT")
+			return err
+		},
+		singleTransaction: true,
+		// Versi sebelumnya mencatat migration SQLite di PRAGMA user_version
+		existing: func(ctx context.Context, conn *sql.Conn) (int, error) {
+			var version int
+			err := conn.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version)
+			return version, err
+		},
+		undefinedTable: isSQLiteUndefinedTable,
+	},
+}
+
+// Kode error "tabel tidak ada" (ER_NO_SUCH_TABLE MySQL, undefined_table PostgreSQL)
+const (
+	mysqlNoSuchTable       = 1146
+	postgresUndefinedTable = "42P01"
+)
+
+// requireBaseline menolak menjalankan migration di database MySQL/Postgres yang tabelnya
+// sudah dibuat manual, karena tidak bisa diketahui migration mana yang sudah diterapkan.
+// Database dianggap kosong hanya jika undefinedTable memastikan tabel users tidak ada;
+// error lain (koneksi putus, hak akses kurang) dikembalikan supaya migration tidak
+// dijalankan ke database yang isinya tidak diketahui.
+func requireBaseline(undefinedTable func(error) bool) func(ctx context.Context, conn *sql.Conn) (int, error) {
+	return func(ctx context.Context, conn *sql.Conn) (int, error) {
+		var one int
+		err := conn.QueryRowContext(ctx, "SELECT 1 FROM users LIMIT 1").Scan(&one)
+		switch {
+		case err != nil && undefinedTable(err):
+			// Tabel users belum ada, database masih kosong
+			return 0, nil
+		case err != nil && !errors.Is(err, sql.ErrNoRows):
+			return 0, fmt.Errorf("gagal memeriksa isi database: %w", err)
+		}
+		return 0, errors.New("database sudah berisi tabel tapi belum punya riwayat di schema_migrations; " +
+			"jalankan `migrate baseline <versi>` dengan versi migration terakhir yang sudah diterapkan manual")
+	}
+}
+
+func isMySQLUndefinedTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlNoSuchTable
+}
+
+func isPostgresUndefinedTable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == postgresUndefinedTable
+}
+
+// Driver SQLite tidak punya kode error khusus untuk tabel yang tidak ada
+func isSQLiteUndefinedTable(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "no such table")
+}
+
+// Migrator menjalankan migration yang di-embed untuk satu database
+type Migrator struct {
+	db         *sql.DB
+	dialect    migrationDialect
+	migrations []Migration
+}
+
+// NewMigrator membaca file migration untuk driver database (mysql, postgres, sqlite)
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	d, ok := migrationDialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("database %q tidak didukung", driver)
+	}
+
+	files, err := migrations.For(driver)
+	if err != nil {
+		return nil, err
+	}
+	list, err := loadMigrations(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: d, migrations: list}, nil
+}
+
+// AutoMigrate membaca env AUTO_MIGRATE: migration dijalankan saat server start kecuali
+// diset false (misalnya jika migration dijalankan terpisah lewat `migrate up`)
+func AutoMigrate() bool {
+	return strings.ToLower(os.Getenv("AUTO_MIGRATE")) != "false"
+}
+
+// loadMigrations membaca semua file NNN_nama.sql, diurutkan berdasarkan versi
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	list := []Migration{}
+	seen := map[int]string{}
+	for _, name := range names {
+		version, err := strconv.Atoi(strings.SplitN(path.Base(name), "_", 2)[0])
+		if err != nil {
+			return nil, fmt.Errorf("nama migration %s harus diawali nomor", name)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migration %s dan %s memakai nomor yang sama", other, name)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+
+		up, down, _ := strings.Cut(string(content), downMarker)
+		if !hasStatements(down) {
+			down = ""
+		}
+		list = append(list, Migration{
+			Version:  version,
+			Name:     name,
+			Up:       up,
+			Down:     down,
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Up menjalankan semua migration yang belum diterapkan secara berurutan dan
+// mengembalikan migration yang berhasil diterapkan. Ditolak jika ada migration yang
+// sudah diterapkan lalu filenya diedit.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int]appliedMigration) error {
+		if changed := m.changed(applied); len(changed) > 0 {
+			return fmt.Errorf("migration sudah diedit setelah diterapkan: %s", strings.Join(changed, ", "))
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			err := m.run(ctx, conn, mig.Up, func(q execer) error {
+				return m.record(ctx, q, mig)
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %v", mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+
+	if err != nil && m.dialect.singleTransaction {
+		done = nil
+	}
+	return done, err
+}
+
+// Down me-rollback steps migration terakhir yang sudah diterapkan, terbaru dulu
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int]appliedMigration) error {
+		versions := make([]int, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for i := 0; i < steps && i < len(versions); i++ {
+			mig, ok := m.find(versions[i])
+			if !ok {
+				return fmt.Errorf("file migration %s tidak ada, tidak bisa di-rollback", applied[versions[i]].name)
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("%s tidak punya bagian down, tidak bisa di-rollback", mig.Name)
+			}
+
+			err := m.run(ctx, conn, mig.Down, func(q execer) error {
+				_, err := q.ExecContext(ctx, m.dialect.delete, mig.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %v", mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+
+	if err != nil && m.dialect.singleTransaction {
+		done = nil
+	}
+	return done, err
+}
+
+// Baseline mencatat migration sampai version sebagai sudah diterapkan tanpa
+// menjalankannya, untuk database yang skemanya dibuat manual sebelum ada schema_migrations
+func (m *Migrator) Baseline(ctx context.Context, version int) ([]Migration, error) {
+	if _, ok := m.find(version); !ok {
+		return nil, fmt.Errorf("migration versi %d tidak ada", version)
+	}
+
+	var done []Migration
+	err := m.lockedRaw(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok || mig.Version > version {
+				continue
+			}
+			if err := m.record(ctx, conn, mig); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status mengembalikan status semua migration: di file, di database, atau keduanya
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Hanya membaca: tanpa lock dan tanpa membuat schema_migrations atau mencatat baseline.
+	// Database yang belum punya schema_migrations dianggap belum menerapkan migration apa pun.
+	applied, err := m.applied(ctx, conn)
+	if err != nil && m.dialect.undefinedTable(err) {
+		applied, err = map[int]appliedMigration{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []MigrationStatus
+	for _, mig := range m.migrations {
+		status := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if a, ok := applied[mig.Version]; ok {
+			appliedAt := a.appliedAt
+			status.AppliedAt = &appliedAt
+			status.Changed = a.checksum != mig.Checksum
+			delete(applied, mig.Version)
+		}
+		list = append(list, status)
+	}
+
+	for version, a := range applied {
+		appliedAt := a.appliedAt
+		list = append(list, MigrationStatus{Version: version, Name: a.name, AppliedAt: &appliedAt, Missing: true})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// locked menjalankan fn sambil memegang lock migration, setelah schema_migrations
+// dipastikan ada dan riwayat migration dibaca
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, applied map[int]appliedMigration) error) error {
+	return m.lockedRaw(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		// Database lama tanpa schema_migrations: ambil riwayat dari cara sebelumnya
+		if len(applied) == 0 {
+			existing, err := m.dialect.existing(ctx, conn)
+			if err != nil {
+				return err
+			}
+			for _, mig := range m.migrations {
+				if mig.Version > existing {
+					break
+				}
+				if err := m.record(ctx, conn, mig); err != nil {
+					return err
+				}
+				applied[mig.Version] = appliedMigration{name: mig.Name, checksum: mig.Checksum, appliedAt: time.Now().UTC()}
+			}
+		}
+
+		return fn(conn, applied)
+	})
+}
+
+// lockedRaw mengambil satu koneksi, memegang lock migration, lalu memastikan tabel
+// schema_migrations ada sebelum menjalankan fn
+func (m *Migrator) lockedRaw(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.dialect.lock(ctx, conn); err != nil {
+		return fmt.Errorf("gagal mengambil lock migration: %v", err)
+	}
+	defer func() {
+		if unlockErr := m.dialect.unlock(ctx, conn, err != nil); err == nil {
+			err = unlockErr
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// applied membaca isi tabel schema_migrations
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]appliedMigration{}
+	for rows.Next() {
+		var version int
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+// changed mengembalikan nama migration yang checksum-nya berbeda dengan saat diterapkan
+func (m *Migrator) changed(applied map[int]appliedMigration) []string {
+	var names []string
+	for _, mig := range m.migrations {
+		if a, ok := applied[mig.Version]; ok && a.checksum != mig.Checksum {
+			names = append(names, mig.Name)
+		}
+	}
+	return names
+}
+
+// run menjalankan script migration lalu after (mencatat atau menghapus riwayat),
+// dalam satu transaksi jika database mendukung DDL transaksional
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, script string, after func(q execer) error) error {
+	var q execer = conn
+	var tx *sql.Tx
+	if m.dialect.transactional {
+		var err error
+		if tx, err = conn.BeginTx(ctx, nil); err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		q = tx
+	}
+
+	statements := []string{script}
+	if m.dialect.splitStatements {
+		statements = splitStatements(script)
+	}
+	for _, statement := range statements {
+		if _, err := q.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if err := after(q); err != nil {
+		return err
+	}
+
+	if tx != nil {
+		return tx.Commit()
+	}
+	return nil
+}
+
+// record mencatat migration sebagai sudah diterapkan
+func (m *Migrator) record(ctx context.Context, q execer, mig Migration) error {
+	appliedAt := time.Now().UTC().Format("2006-01-02 15:04:05")
+	_, err := q.ExecContext(ctx, m.dialect.insert, mig.Version, mig.Name, mig.Checksum, appliedAt)
+	return err
+}
+
+func (m *Migrator) find(version int) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+// splitStatements memecah script menjadi statement per baris yang diakhiri titik koma.
+// Cukup untuk file migration MySQL yang tidak berisi stored procedure.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		current.WriteString(line + "\n")
+		if strings.HasSuffix(strings.TrimSpace(line), ";") {
+			if hasStatements(current.String()) {
+				statements = append(statements, current.String())
+			}
+			current.Reset()
+		}
+	}
+	if hasStatements(current.String()) {
+		statements = append(statements, current.String())
+	}
+	return statements
+}
+
+// hasStatements mengecek apakah script berisi sesuatu selain komentar dan spasi
+func hasStatements(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return true
+		}
+	}
+	return false
+}
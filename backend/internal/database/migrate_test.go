@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestUndefinedTableErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		mysql, postgres bool
+	}{
+		{"mysql tabel tidak ada", &mysql.MySQLError{Number: mysqlNoSuchTable}, true, false},
+		{"mysql dibungkus", fmt.Errorf("query: %w", &mysql.MySQLError{Number: mysqlNoSuchTable}), true, false},
+		{"mysql akses ditolak", &mysql.MySQLError{Number: 1142}, false, false},
+		{"postgres tabel tidak ada", &pgconn.PgError{Code: postgresUndefinedTable}, false, true},
+		{"postgres akses ditolak", &pgconn.PgError{Code: "42501"}, false, false},
+		{"koneksi putus", sql.ErrConnDone, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := isMySQLUndefinedTable(tt.err); got != tt.mysql {
+			t.Errorf("%s: isMySQLUndefinedTable = %v, seharusnya %v", tt.name, got, tt.mysql)
+		}
+		if got := isPostgresUndefinedTable(tt.err); got != tt.postgres {
+			t.Errorf("%s: isPostgresUndefinedTable = %v, seharusnya %v", tt.name, got, tt.postgres)
+		}
+	}
+}
+
+// requireBaseline dijalankan di atas SQLite; undefinedTable menentukan error mana yang
+// dianggap "tabel users belum ada"
+func TestRequireBaseline(t *testing.T) {
+	ctx := context.Background()
+	noSuchTable := func(err error) bool { return strings.Contains(err.Error(), "no such table") }
+	never := func(error) bool { return false }
+
+	open := func(t *testing.T, schema string) *sql.Conn {
+		t.Helper()
+		db, err := sql.Open("sqlite", SQLiteDSN(":memory:"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		if schema != "" {
+			if _, err := conn.ExecContext(ctx, schema); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return conn
+	}
+
+	t.Run("database kosong", func(t *testing.T) {
+		if _, err := requireBaseline(noSuchTable)(ctx, open(t, "")); err != nil {
+			t.Fatalf("database kosong seharusnya boleh dimigrasi, err = %v", err)
+		}
+	})
+
+	t.Run("tabel sudah ada", func(t *testing.T) {
+		_, err := requireBaseline(noSuchTable)(ctx, open(t, "CREATE TABLE users (id INTEGER)"))
+		if err == nil || !strings.Contains(err.Error(), "migrate baseline") {
+			t.Fatalf("seharusnya diminta baseline, err = %v", err)
+		}
+	})
+
+	t.Run("error lain tidak dianggap database kosong", func(t *testing.T) {
+		_, err := requireBaseline(never)(ctx, open(t, ""))
+		if err == nil || strings.Contains(err.Error(), "migrate baseline") {
+			t.Fatalf("error query seharusnya dikembalikan, err = %v", err)
+		}
+	})
+
+	t.Run("koneksi tertutup", func(t *testing.T) {
+		conn := open(t, "")
+		conn.Close()
+		if _, err := requireBaseline(noSuchTable)(ctx, conn); !errors.Is(err, sql.ErrConnDone) {
+			t.Fatalf("err = %v, seharusnya sql.ErrConnDone", err)
+		}
+	})
+}
+
+// Status tidak boleh menulis apa pun: tidak membuat schema_migrations dan tidak mencatat
+// baseline dari PRAGMA user_version, itu tugas Up
+func TestStatusReadOnly(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", SQLiteDSN(":memory:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	m, err := NewMigrator(db, SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Database versi lama: migration 1 sudah diterapkan tapi hanya tercatat di user_version
+	if _, err := db.ExecContext(ctx, "PRAGMA user_version = 1"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(m.migrations) {
+		t.Fatalf("Status mengembalikan %d migration, seharusnya %d", len(list), len(m.migrations))
+	}
+	for _, s := range list {
+		if s.AppliedAt != nil {
+			t.Fatalf("migration %d tercatat diterapkan sebelum Up", s.Version)
+		}
+	}
+	var tables int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_migrations'").Scan(&tables); err != nil || tables != 0 {
+		t.Fatalf("Status membuat tabel schema_migrations (%d, %v)", tables, err)
+	}
+
+	// Setelah Up, Status membaca riwayat yang ditulis Up
+	if _, err := db.ExecContext(ctx, "PRAGMA user_version = 0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if list, err = m.Status(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range list {
+		if s.AppliedAt == nil || s.Changed || s.Missing {
+			t.Fatalf("status migration %d setelah Up: %+v", s.Version, s)
+		}
+	}
+}
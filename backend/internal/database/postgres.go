@@ -0,0 +1,42 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// connectPostgres membuat koneksi ke PostgreSQL memakai env DB_* yang sama dengan MySQL,
+// ditambah DB_SSLMODE (default disable)
+func connectPostgres() (*sql.DB, error) {
+	sslMode := os.Getenv("DB_SSLMODE")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD")),
+		Host:     net.JoinHostPort(os.Getenv("DB_HOST"), os.Getenv("DB_PORT")),
+		Path:     "/" + os.Getenv("DB_NAME"),
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+
+	db, err := sql.Open("pgx", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("error membuka koneksi database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error ping database: %v", err)
+	}
+
+	log.Println("✅ Koneksi database PostgreSQL berhasil")
+	return db, nil
+}
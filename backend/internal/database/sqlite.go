@@ -0,0 +1,48 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	_ "modernc.org/sqlite"
+)
+
+// Parameter koneksi SQLite: menunggu sampai 5 detik jika database sedang dikunci (dipasang
+// paling awal supaya pragma berikutnya ikut menunggu), foreign key aktif (ON DELETE
+// CASCADE / SET NULL), dan WAL supaya pembaca tidak menunggu penulis.
+// _txlock=immediate membuat setiap transaksi langsung mengambil lock write, pengganti
+// SELECT ... FOR UPDATE yang tidak ada di SQLite.
+const sqliteParams = "_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_txlock=immediate"
+
+// SQLiteDSN membuat DSN driver sqlite untuk file (atau :memory:) beserta parameter koneksinya
+func SQLiteDSN(file string) string {
+	return file + "?" + sqliteParams
+}
+
+// connectSQLite membuka file database SQLite di DB_PATH (default notes.db)
+func connectSQLite() (*sql.DB, error) {
+	file := os.Getenv("DB_PATH")
+	if file == "" {
+		file = "notes.db"
+	}
+
+	db, err := sql.Open("sqlite", SQLiteDSN(file))
+	if err != nil {
+		return nil, fmt.Errorf("error membuka database SQLite: %v", err)
+	}
+
+	// Setiap koneksi ke :memory: adalah database terpisah, jadi cukup satu koneksi
+	if file == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error ping database: %v", err)
+	}
+
+	log.Printf("✅ Koneksi database SQLite berhasil (%s)\n", file)
+	return db, nil
+}
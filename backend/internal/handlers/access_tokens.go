@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"notes-api/internal/middleware"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Jumlah karakter awal token (setelah "pat_") yang disimpan untuk ditampilkan
+const accessTokenPrefixLen = 8
+
+// GetAccessTokens mengambil semua personal access token milik user (tanpa nilai token)
+func (h *Handler) GetAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	tokens, err := h.Tokens.ListAccessTokens(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data token")
+		return
+	}
+
+	utils.WriteSuccess(w, "Data token berhasil diambil", tokens)
+}
+
+// CreateAccessToken membuat personal access token baru. Nilai token hanya dikembalikan sekali ini.
+func (h *Handler) CreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.CreateAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Nama token wajib diisi")
+		return
+	}
+
+	if len(req.Scopes) == 0 {
+		utils.WriteError(w, http.StatusBadRequest, "Minimal satu scope wajib dipilih ("+strings.Join(middleware.AllScopes, ", ")+")")
+		return
+	}
+
+	scopes := []string{}
+	seen := map[string]bool{}
+	for _, scope := range req.Scopes {
+		if !middleware.ValidScope(scope) {
+			utils.WriteError(w, http.StatusBadRequest, "Scope "+scope+" tidak dikenal")
+			return
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		utils.WriteError(w, http.StatusBadRequest, "expires_at harus di masa depan")
+		return
+	}
+
+	// Hash disimpan dari token lengkap (termasuk "pat_"), bukan dari secret-nya saja
+	secret, _, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat token")
+		return
+	}
+	token := middleware.AccessTokenPrefix + secret
+	prefix := token[:len(middleware.AccessTokenPrefix)+accessTokenPrefixLen]
+
+	pat := models.PersonalAccessToken{
+		Name:      req.Name,
+		Prefix:    prefix,
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := h.Tokens.CreateAccessToken(r.Context(), userID, &pat, utils.HashToken(token)); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat token")
+		return
+	}
+
+	utils.WriteSuccess(w, "Token berhasil dibuat, simpan sekarang karena tidak akan ditampilkan lagi", models.CreateAccessTokenResponse{
+		Token:               token,
+		PersonalAccessToken: pat,
+	})
+}
+
+// RevokeAccessToken menghapus personal access token sehingga langsung tidak bisa dipakai lagi
+func (h *Handler) RevokeAccessToken(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	tokenID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	err := h.Tokens.DeleteAccessToken(r.Context(), userID, tokenID)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Token tidak ditemukan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mencabut token")
+		return
+	}
+
+	utils.WriteSuccess(w, "Token berhasil dicabut", nil)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"testing"
+)
+
+// noteResponse adalah response endpoint yang mengembalikan satu catatan
+type noteResponse struct {
+	Data models.Note `json:"data"`
+}
+
+// expectStatus menggagalkan test jika status response tidak sesuai
+func expectStatus(t *testing.T, name string, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("%s: status %d, seharusnya %d: %s", name, rec.Code, want, rec.Body.String())
+	}
+}
+
+func TestAuthFlow(t *testing.T) {
+	forEachStore(t, func(t *testing.T, api http.Handler, _ *store.Store) {
+		register := models.RegisterRequest{Username: "budi", Email: "budi@example.com", Password: "rahasia123"}
+		expectStatus(t, "register", doWithToken(t, api, "", http.MethodPost, "/api/register", register), http.StatusOK)
+		expectStatus(t, "register duplikat", doWithToken(t, api, "", http.MethodPost, "/api/register", register), http.StatusConflict)
+
+		wrong := models.LoginRequest{Email: register.Email, Password: "salah"}
+		expectStatus(t, "login password salah", doWithToken(t, api, "", http.MethodPost, "/api/login", wrong), http.StatusUnauthorized)
+
+		rec := doWithToken(t, api, "", http.MethodPost, "/api/login", models.LoginRequest{Email: register.Email, Password: register.Password})
+		expectStatus(t, "login", rec, http.StatusOK)
+		var login struct {
+			Data models.LoginResponse `json:"data"`
+		}
+		decode(t, rec, &login)
+		if login.Data.Token == "" || login.Data.RefreshToken == "" {
+			t.Fatalf("login tidak mengembalikan token: %s", rec.Body.String())
+		}
+
+		// Endpoint yang butuh login menolak request tanpa token atau dengan token palsu
+		expectStatus(t, "tanpa token", doWithToken(t, api, "", http.MethodGet, "/api/me", nil), http.StatusUnauthorized)
+		expectStatus(t, "token palsu", doWithToken(t, api, "bukan.token.jwt", http.MethodGet, "/api/me", nil), http.StatusUnauthorized)
+
+		rec = doWithToken(t, api, login.Data.Token, http.MethodGet, "/api/me", nil)
+		expectStatus(t, "me", rec, http.StatusOK)
+		var me struct {
+			Data models.User `json:"data"`
+		}
+		decode(t, rec, &me)
+		if me.Data.Username != "budi" {
+			t.Fatalf("me mengembalikan user %q", me.Data.Username)
+		}
+
+		// Default UNVERIFIED_ACCESS=readonly: user yang belum verifikasi email hanya bisa membaca
+		expectStatus(t, "baca sebelum verifikasi", doWithToken(t, api, login.Data.Token, http.MethodGet, "/api/notes", nil), http.StatusOK)
+		note := models.Note{Title: "Catatan"}
+		expectStatus(t, "tulis sebelum verifikasi", doWithToken(t, api, login.Data.Token, http.MethodPost, "/api/notes", note), http.StatusForbidden)
+
+		// Setelah logout, access token yang sama tidak berlaku lagi
+		logout := models.RefreshRequest{RefreshToken: login.Data.RefreshToken}
+		expectStatus(t, "logout", doWithToken(t, api, login.Data.Token, http.MethodPost, "/api/logout", logout), http.StatusOK)
+		expectStatus(t, "setelah logout", doWithToken(t, api, login.Data.Token, http.MethodGet, "/api/me", nil), http.StatusUnauthorized)
+		expectStatus(t, "refresh setelah logout", doWithToken(t, api, "", http.MethodPost, "/api/token/refresh", logout), http.StatusUnauthorized)
+	})
+}
+
+func TestNotesCRUD(t *testing.T) {
+	forEachStore(t, func(t *testing.T, api http.Handler, st *store.Store) {
+		token := accessToken(t, st, seedUser(t, st, "budi"))
+
+		expectStatus(t, "buat tanpa judul", doWithToken(t, api, token, http.MethodPost, "/api/notes", models.Note{Content: "isi"}), http.StatusBadRequest)
+
+		rec := doWithToken(t, api, token, http.MethodPost, "/api/notes", models.Note{Title: "Belanja", Content: "susu"})
+		expectStatus(t, "buat", rec, http.StatusOK)
+		var created noteResponse
+		decode(t, rec, &created)
+		if created.Data.ID == 0 || rec.Header().Get("ETag") == "" {
+			t.Fatalf("catatan baru tanpa ID atau ETag: %s", rec.Body.String())
+		}
+		path := fmt.Sprintf("/api/notes/%d", created.Data.ID)
+
+		update := models.Note{Title: "Belanja mingguan", Content: "susu, roti"}
+		expectStatus(t, "update", doWithToken(t, api, token, http.MethodPut, path, update), http.StatusOK)
+		expectStatus(t, "patch", doWithToken(t, api, token, http.MethodPatch, path, map[string]bool{"is_favorite": true}), http.StatusOK)
+
+		rec = doWithToken(t, api, token, http.MethodGet, path, nil)
+		expectStatus(t, "ambil", rec, http.StatusOK)
+		var got noteResponse
+		decode(t, rec, &got)
+		if got.Data.Title != update.Title || got.Data.Content != update.Content || !got.Data.IsFavorite {
+			t.Fatalf("catatan tidak sesuai setelah update: %+v", got.Data)
+		}
+
+		rec = doWithToken(t, api, token, http.MethodGet, "/api/notes", nil)
+		expectStatus(t, "list", rec, http.StatusOK)
+		var list listResponse
+		decode(t, rec, &list)
+		if len(list.Data) != 1 || list.Data[0].ID != created.Data.ID {
+			t.Fatalf("list seharusnya berisi catatan %d: %s", created.Data.ID, rec.Body.String())
+		}
+
+		expectStatus(t, "hapus", doWithToken(t, api, token, http.MethodDelete, path, nil), http.StatusOK)
+		expectStatus(t, "ambil setelah dihapus", doWithToken(t, api, token, http.MethodGet, path, nil), http.StatusNotFound)
+		expectStatus(t, "hapus dua kali", doWithToken(t, api, token, http.MethodDelete, path, nil), http.StatusNotFound)
+		expectStatus(t, "catatan tidak ada", doWithToken(t, api, token, http.MethodGet, "/api/notes/9999", nil), http.StatusNotFound)
+
+		rec = doWithToken(t, api, token, http.MethodGet, "/api/notes", nil)
+		decode(t, rec, &list)
+		if len(list.Data) != 0 {
+			t.Fatalf("catatan di trash tidak boleh muncul di list: %s", rec.Body.String())
+		}
+	})
+}
+
+// Data user lain diperlakukan seperti tidak ada (404), bukan 403, supaya ID milik user
+// lain tidak bisa ditebak
+func TestNoteOwnership(t *testing.T) {
+	forEachStore(t, func(t *testing.T, api http.Handler, st *store.Store) {
+		owner := accessToken(t, st, seedUser(t, st, "budi"))
+		other := accessToken(t, st, seedUser(t, st, "ani"))
+
+		rec := doWithToken(t, api, owner, http.MethodPost, "/api/folders", models.Folder{Name: "Kerja"})
+		expectStatus(t, "buat folder", rec, http.StatusOK)
+		var folder struct {
+			Data models.Folder `json:"data"`
+		}
+		decode(t, rec, &folder)
+
+		rec = doWithToken(t, api, owner, http.MethodPost, "/api/tags", models.Tag{Name: "penting"})
+		expectStatus(t, "buat tag", rec, http.StatusOK)
+		var tag struct {
+			Data models.Tag `json:"data"`
+		}
+		decode(t, rec, &tag)
+
+		rec = doWithToken(t, api, owner, http.MethodPost, "/api/notes", models.Note{Title: "Rahasia", FolderID: &folder.Data.ID})
+		expectStatus(t, "buat catatan", rec, http.StatusOK)
+		var note noteResponse
+		decode(t, rec, &note)
+		notePath := fmt.Sprintf("/api/notes/%d", note.Data.ID)
+
+		requests := []struct {
+			name, method, path string
+			body               interface{}
+		}{
+			{"ambil catatan", http.MethodGet, notePath, nil},
+			{"update catatan", http.MethodPut, notePath, models.Note{Title: "Diambil alih"}},
+			{"patch catatan", http.MethodPatch, notePath, map[string]string{"title": "Diambil alih"}},
+			{"hapus catatan", http.MethodDelete, notePath, nil},
+			{"revisi catatan", http.MethodGet, notePath + "/revisions", nil},
+			{"catatan di folder", http.MethodGet, fmt.Sprintf("/api/folders/%d/notes", folder.Data.ID), nil},
+			{"catatan dengan tag", http.MethodGet, fmt.Sprintf("/api/tags/%d/notes", tag.Data.ID), nil},
+			{"buat catatan di folder", http.MethodPost, "/api/notes", models.Note{Title: "Titip", FolderID: &folder.Data.ID}},
+			{"pasang tag", http.MethodPost, fmt.Sprintf("%s/tags/%d", notePath, tag.Data.ID), nil},
+			{"hapus folder", http.MethodDelete, fmt.Sprintf("/api/folders/%d", folder.Data.ID), nil},
+			{"hapus tag", http.MethodDelete, fmt.Sprintf("/api/tags/%d", tag.Data.ID), nil},
+		}
+		for _, req := range requests {
+			expectStatus(t, req.name, doWithToken(t, api, other, req.method, req.path, req.body), http.StatusNotFound)
+		}
+
+		rec = doWithToken(t, api, other, http.MethodGet, "/api/notes", nil)
+		var list listResponse
+		decode(t, rec, &list)
+		if len(list.Data) != 0 {
+			t.Fatalf("list user lain tidak boleh berisi catatan pemilik: %s", rec.Body.String())
+		}
+
+		// Data pemilik tidak berubah
+		rec = doWithToken(t, api, owner, http.MethodGet, notePath, nil)
+		expectStatus(t, "pemilik ambil catatan", rec, http.StatusOK)
+		var got noteResponse
+		decode(t, rec, &got)
+		if got.Data.Title != "Rahasia" {
+			t.Fatalf("catatan pemilik berubah: %+v", got.Data)
+		}
+	})
+}
@@ -1,18 +1,23 @@
 package handlers
 
 import (
-	"database/sql"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
-	"notes-api/internal/database"
+	"notes-api/internal/middleware"
 	"notes-api/internal/models"
+	"notes-api/internal/ratelimit"
+	"notes-api/internal/store"
 	"notes-api/internal/utils"
 	"strings"
 )
 
+// Pesan untuk akun yang dinonaktifkan admin (notes-api user disable)
+const accountDisabledMessage = "Akun dinonaktifkan, hubungi admin"
 
 // Register handler untuk registrasi user baru
-func Register(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req models.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -33,32 +38,33 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Insert ke database
-	query := "INSERT INTO users (username, email, password_hash, full_name) VALUES (?, ?, ?, ?)"
-	result, err := database.DB.Exec(query, req.Username, req.Email, hashedPassword, req.FullName)
+	// Simpan user baru
+	user := models.User{Username: req.Username, Email: req.Email, PasswordHash: hashedPassword, FullName: req.FullName}
+	err = h.Users.Create(r.Context(), &user)
+	if errors.Is(err, store.ErrDuplicate) {
+		utils.WriteError(w, http.StatusConflict, "Username atau email sudah digunakan")
+		return
+	}
 	if err != nil {
-		// Cek error duplicate entry (unique constraint)
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			utils.WriteError(w, http.StatusConflict, "Username atau email sudah digunakan")
-			return
-		}
 		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat user")
 		return
 	}
 
-	// Ambil ID user yang baru dibuat
-	userID, _ := result.LastInsertId()
+	// Kirim link verifikasi email; kalau gagal user masih bisa minta kirim ulang
+	if err := h.sendVerificationEmail(r.Context(), user.ID, req.Email); err != nil {
+		log.Println("Gagal mengirim email verifikasi:", err)
+	}
 
 	// Return success
 	utils.WriteSuccess(w, "Registrasi berhasil", map[string]interface{}{
-		"id":       userID,
+		"id":       user.ID,
 		"username": req.Username,
 		"email":    req.Email,
 	})
 }
 
 // Login handler untuk login user
-func Login(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req models.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -72,14 +78,16 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Cari user di database
-	var user models.User
-	query := "SELECT id, username, email, password_hash, full_name, created_at FROM users WHERE email = ?"
-	err := database.DB.QueryRow(query, req.Email).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.FullName, &user.CreatedAt,
-	)
+	// Cek rate limit sebelum bcrypt supaya login tidak bisa dipakai untuk brute force atau membebani CPU
+	ip := ratelimit.ClientIP(r)
+	if !h.checkLoginLimit(w, ip, req.Email) {
+		return
+	}
 
-	if err == sql.ErrNoRows {
+	// Cari user di database
+	user, err := h.Users.GetByEmail(r.Context(), req.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		h.recordLoginFailure(ip, req.Email)
 		utils.WriteError(w, http.StatusUnauthorized, "Email atau password salah")
 		return
 	}
@@ -90,20 +98,67 @@ func Login(w http.ResponseWriter, r *http.Request) {
 
 	// Cek password
 	if !utils.CheckPassword(req.Password, user.PasswordHash) {
+		h.recordLoginFailure(ip, req.Email)
 		utils.WriteError(w, http.StatusUnauthorized, "Email atau password salah")
 		return
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	if user.DisabledAt != nil {
+		utils.WriteError(w, http.StatusForbidden, accountDisabledMessage)
+		return
+	}
+
+	if user.EmailVerifiedAt == nil && middleware.UnverifiedAccess() == middleware.UnverifiedNone {
+		utils.WriteError(w, http.StatusForbidden, "Email belum diverifikasi, cek email kamu untuk link verifikasi")
+		return
+	}
+
+	// Jika 2FA aktif, token baru diberikan setelah kode 2FA dikirim ke /api/login/2fa.
+	// Hitungan gagal akun baru di-reset setelah tahap kedua berhasil.
+	if user.TwoFactorEnabled {
+		challenge, err := h.createLoginChallenge(r.Context(), user.ID)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses login")
+			return
+		}
+		utils.WriteSuccess(w, "Masukkan kode 2FA", challenge)
+		return
+	}
+
+	// Generate access token dan refresh token
+	resp, err := h.issueTokens(r.Context(), user)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat token")
 		return
 	}
 
+	if err := h.LoginLimit.Succeeded(user.Email); err != nil {
+		log.Println("Gagal me-reset rate limit login:", err)
+	}
+
 	// Return token dan data user
-	utils.WriteSuccess(w, "Login berhasil", models.LoginResponse{
-		Token: token,
-		User:  user,
-	})
+	utils.WriteSuccess(w, "Login berhasil", resp)
+}
+
+// checkLoginLimit menulis 429 dengan header Retry-After dan return false jika IP atau akun
+// sedang diblokir karena terlalu banyak percobaan login gagal
+func (h *Handler) checkLoginLimit(w http.ResponseWriter, ip, account string) bool {
+	wait, err := h.LoginLimit.Check(ip, account)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses login")
+		return false
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", ratelimit.RetryAfterSeconds(wait))
+		utils.WriteError(w, http.StatusTooManyRequests, "Terlalu banyak percobaan login, coba lagi dalam "+ratelimit.RetryAfterSeconds(wait)+" detik")
+		return false
+	}
+	return true
+}
+
+// recordLoginFailure mencatat login gagal; error store hanya di-log supaya response tetap 401
+func (h *Handler) recordLoginFailure(ip, account string) {
+	if err := h.LoginLimit.Failed(ip, account); err != nil {
+		log.Println("Gagal mencatat login gagal:", err)
+	}
 }
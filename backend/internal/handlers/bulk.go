@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"notes-api/internal/middleware"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+)
+
+// Jumlah catatan maksimal dalam satu request bulk
+const maxBulkNotes = 500
+
+// BulkNotes menjalankan satu aksi (pindah folder, tambah/hapus tag, favorit, hapus)
+// ke banyak catatan dalam satu transaksi. Catatan yang bukan milik user dilaporkan gagal.
+func (h *Handler) BulkNotes(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.BulkNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	if len(req.NoteIDs) == 0 {
+		utils.WriteError(w, http.StatusBadRequest, "note_ids wajib diisi")
+		return
+	}
+	if len(req.NoteIDs) > maxBulkNotes {
+		utils.WriteError(w, http.StatusBadRequest, "Maksimal 500 catatan per request")
+		return
+	}
+
+	switch req.Action {
+	case "move", "favorite", "unfavorite", "delete":
+	case "add_tags", "remove_tags":
+		if len(req.TagIDs) == 0 {
+			utils.WriteError(w, http.StatusBadRequest, "tag_ids wajib diisi untuk action "+req.Action)
+			return
+		}
+		if !middleware.HasScope(r, middleware.ScopeTagsWrite) {
+			utils.WriteError(w, http.StatusForbidden, "Token tidak punya scope "+middleware.ScopeTagsWrite)
+			return
+		}
+	default:
+		utils.WriteError(w, http.StatusBadRequest, "Action harus move, add_tags, remove_tags, favorite, unfavorite, atau delete")
+		return
+	}
+
+	ownedIDs, err := h.Notes.Bulk(r.Context(), userID, req)
+	if errors.Is(err, store.ErrFolderNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan")
+		return
+	}
+	if errors.Is(err, store.ErrTagNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Satu atau lebih tag tidak ditemukan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses aksi bulk")
+		return
+	}
+
+	// Catatan yang bukan milik user (atau ada di trash) dilaporkan gagal
+	owned := map[int]bool{}
+	for _, id := range ownedIDs {
+		owned[id] = true
+	}
+
+	results := make([]models.BulkNoteResult, 0, len(req.NoteIDs))
+	for _, id := range store.UniqueIDs(req.NoteIDs) {
+		if !owned[id] {
+			results = append(results, models.BulkNoteResult{ID: id, Error: "Catatan tidak ditemukan"})
+			continue
+		}
+		results = append(results, models.BulkNoteResult{ID: id, Success: true})
+	}
+
+	utils.WriteSuccess(w, "Aksi bulk berhasil diproses", map[string]interface{}{
+		"action":    req.Action,
+		"succeeded": len(ownedIDs),
+		"failed":    len(results) - len(ownedIDs),
+		"results":   results,
+	})
+}
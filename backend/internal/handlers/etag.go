@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"notes-api/internal/models"
+	"notes-api/internal/utils"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// noteETag membuat ETag dari version counter catatan
+func noteETag(note models.Note) string {
+	return `"` + strconv.Itoa(note.ID) + "-" + strconv.Itoa(note.Version) + `"`
+}
+
+// etagMatches mengecek apakah header If-Match / If-None-Match berisi etag.
+// Mendukung "*" dan daftar ETag dipisah koma. Dengan weak=true (If-None-Match) prefix W/
+// diabaikan; tanpa weak (If-Match) ETag W/ tidak pernah cocok karena If-Match wajib
+// memakai strong comparison (RFC 9110 bagian 13.1.1).
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// preconditionError dikembalikan checkIfMatch jika header If-Match tidak terpenuhi
+type preconditionError struct {
+	missing bool // If-Match wajib tapi tidak dikirim
+	current models.Note
+}
+
+func (e *preconditionError) Error() string {
+	if e.missing {
+		return "Header If-Match wajib dikirim"
+	}
+	return "Catatan sudah diubah di tempat lain, muat ulang versi terbaru"
+}
+
+// checkIfMatch memvalidasi header If-Match terhadap versi catatan di server.
+// Dipanggil di dalam callback store supaya pengecekan dan penyimpanan terjadi
+// dalam satu transaksi. Header If-Match wajib dikirim jika env REQUIRE_IF_MATCH=true.
+func checkIfMatch(r *http.Request, current models.Note) error {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		if os.Getenv("REQUIRE_IF_MATCH") == "true" {
+			return &preconditionError{missing: true}
+		}
+		return nil
+	}
+
+	if etagMatches(ifMatch, noteETag(current), false) {
+		return nil
+	}
+	return &preconditionError{current: current}
+}
+
+// writePreconditionError menulis response 428, atau 412 beserta versi terbaru catatan
+func writePreconditionError(w http.ResponseWriter, err *preconditionError) {
+	if err.missing {
+		utils.WriteError(w, http.StatusPreconditionRequired, err.Error())
+		return
+	}
+
+	w.Header().Set("ETag", noteETag(err.current))
+	utils.WriteJSON(w, http.StatusPreconditionFailed, utils.Response{
+		Success: false,
+		Message: err.Error(),
+		Data:    err.current,
+	})
+}
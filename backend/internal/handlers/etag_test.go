@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"notes-api/internal/models"
+	"notes-api/internal/store/memory"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestETagMatches(t *testing.T) {
+	const etag = `"7-3"`
+	tests := []struct {
+		header      string
+		weak, match bool
+	}{
+		{`"7-3"`, false, true},
+		{`"7-3"`, true, true},
+		{`"7-2", "7-3"`, false, true},
+		{`*`, false, true},
+		{`"7-2"`, false, false},
+		{`"7-2"`, true, false},
+		{`W/"7-3"`, true, true},
+		{`W/"7-3"`, false, false},
+		{`"7-2", W/"7-3"`, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, etag, tt.weak); got != tt.match {
+			t.Errorf("etagMatches(%s, %s, weak=%v) = %v, seharusnya %v", tt.header, etag, tt.weak, got, tt.match)
+		}
+	}
+}
+
+// If-Match dengan ETag W/ harus ditolak meskipun nilainya sama dengan versi di server,
+// sedangkan If-None-Match tetap memakai weak comparison
+func TestIfMatchUsesStrongComparison(t *testing.T) {
+	st := memory.New()
+	h := New(st)
+	r := chi.NewRouter()
+	r.Get("/api/notes/{id}", h.GetNoteByID)
+	r.Put("/api/notes/{id}", h.UpdateNote)
+
+	userID := seedUser(t, st, "budi")
+	note := models.Note{UserID: userID, Title: "Judul", Content: "isi"}
+	if err := st.Notes.Create(context.Background(), &note); err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/api/notes/%d", note.ID)
+	etag := noteETag(note)
+
+	req := newRequestAs(t, userID, http.MethodGet, path, nil)
+	req.Header.Set("If-None-Match", "W/"+etag)
+	if rec := serve(r, req); rec.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match W/: status %d, seharusnya 304", rec.Code)
+	}
+
+	update := models.Note{Title: "Judul baru", Content: "isi baru"}
+	req = newRequestAs(t, userID, http.MethodPut, path, update)
+	req.Header.Set("If-Match", "W/"+etag)
+	if rec := serve(r, req); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("If-Match W/: status %d, seharusnya 412", rec.Code)
+	}
+
+	req = newRequestAs(t, userID, http.MethodPut, path, update)
+	req.Header.Set("If-Match", etag)
+	if rec := serve(r, req); rec.Code != http.StatusOK {
+		t.Fatalf("If-Match strong: status %d, seharusnya 200: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"notes-api/internal/database"
 	"notes-api/internal/middleware"
 	"notes-api/internal/models"
+	"notes-api/internal/store"
 	"notes-api/internal/utils"
 	"strconv"
 	"strings"
@@ -14,31 +15,50 @@ import (
 )
 
 // GetFolders mengambil semua folder milik user
-func GetFolders(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetFolders(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 
-	query := "SELECT id, user_id, name, created_at FROM folders WHERE user_id = ? ORDER BY created_at DESC"
-	rows, err := database.DB.Query(query, userID)
+	folders, err := h.Folders.List(r.Context(), userID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data folder")
 		return
 	}
-	defer rows.Close()
 
-	folders := []models.Folder{}
-	for rows.Next() {
-		var folder models.Folder
-		if err := rows.Scan(&folder.ID, &folder.UserID, &folder.Name, &folder.CreatedAt); err != nil {
-			continue
+	utils.WriteSuccess(w, "Data folder berhasil diambil", folders)
+}
+
+// GetFolderTree mengambil semua folder milik user dalam bentuk pohon
+func (h *Handler) GetFolderTree(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	folders, err := h.Folders.List(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data folder")
+		return
+	}
+
+	byID := map[int]*models.Folder{}
+	for i := range folders {
+		byID[folders[i].ID] = &folders[i]
+	}
+
+	roots := []*models.Folder{}
+	for i := range folders {
+		folder := &folders[i]
+		if folder.ParentID != nil {
+			if parent, ok := byID[*folder.ParentID]; ok {
+				parent.Children = append(parent.Children, folder)
+				continue
+			}
 		}
-		folders = append(folders, folder)
+		roots = append(roots, folder)
 	}
 
-	utils.WriteSuccess(w, "Data folder berhasil diambil", folders)
+	utils.WriteSuccess(w, "Pohon folder berhasil diambil", roots)
 }
 
-// CreateFolder membuat folder baru
-func CreateFolder(w http.ResponseWriter, r *http.Request) {
+// CreateFolder membuat folder baru, opsional di dalam parent_id
+func (h *Handler) CreateFolder(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 
 	var folder models.Folder
@@ -52,69 +72,158 @@ func CreateFolder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := "INSERT INTO folders (user_id, name) VALUES (?, ?)"
-	result, err := database.DB.Exec(query, userID, folder.Name)
+	folder.UserID = userID
+	err := h.Folders.Create(r.Context(), &folder)
+	if errors.Is(err, store.ErrFolderNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Parent folder tidak ditemukan")
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat folder")
 		return
 	}
 
-	folderID, _ := result.LastInsertId()
-	folder.ID = int(folderID)
-	folder.UserID = userID
-
 	utils.WriteSuccess(w, "Folder berhasil dibuat", folder)
 }
 
 // UpdateFolder mengupdate nama folder
-func UpdateFolder(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateFolder(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	folderID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	var folder models.Folder
-	if err := json.NewDecoder(r.Body).Decode(&folder); err != nil {
+	var input models.Folder
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
 		return
 	}
 
-	if strings.TrimSpace(folder.Name) == "" {
+	if strings.TrimSpace(input.Name) == "" {
 		utils.WriteError(w, http.StatusBadRequest, "Nama folder wajib diisi")
 		return
 	}
 
-	query := "UPDATE folders SET name = ? WHERE id = ? AND user_id = ?"
-	result, err := database.DB.Exec(query, folder.Name, folderID, userID)
-	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengupdate folder")
+	_, err := h.Folders.Update(r.Context(), userID, folderID, func(folder *models.Folder) error {
+		folder.Name = input.Name
+		return nil
+	})
+	if writeFolderUpdateError(w, err, "Gagal mengupdate folder") {
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan")
+	utils.WriteSuccess(w, "Folder berhasil diupdate", nil)
+}
+
+// MoveFolder memindahkan folder ke parent lain. Ditolak jika parent baru
+// adalah folder itu sendiri atau salah satu sub-foldernya (akan membuat siklus).
+func (h *Handler) MoveFolder(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	folderID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	var req models.MoveFolderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
 		return
 	}
 
-	utils.WriteSuccess(w, "Folder berhasil diupdate", nil)
+	_, err := h.Folders.Update(r.Context(), userID, folderID, func(folder *models.Folder) error {
+		folder.ParentID = req.ParentID
+		return nil
+	})
+	if writeFolderUpdateError(w, err, "Gagal memindahkan folder") {
+		return
+	}
+
+	utils.WriteSuccess(w, "Folder berhasil dipindahkan", nil)
 }
 
-// DeleteFolder menghapus folder
-func DeleteFolder(w http.ResponseWriter, r *http.Request) {
+// PatchFolder mengupdate sebagian field folder mengikuti JSON Merge Patch (RFC 7396).
+// Field name tidak boleh null, parent_id null berarti folder dipindah ke root.
+func (h *Handler) PatchFolder(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	folderID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	query := "DELETE FROM folders WHERE id = ? AND user_id = ?"
-	result, err := database.DB.Exec(query, folderID, userID)
+	patch, err := decodeMergePatch(r)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal menghapus folder")
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var name *string
+	var parentID *int
+	if raw, ok := patch["name"]; ok {
+		if err := json.Unmarshal(raw, &name); err != nil || name == nil || strings.TrimSpace(*name) == "" {
+			utils.WriteError(w, http.StatusBadRequest, "Nama folder wajib diisi")
+			return
+		}
+	}
+	if raw, ok := patch["parent_id"]; ok {
+		if err := json.Unmarshal(raw, &parentID); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Field parent_id tidak valid")
+			return
+		}
+	}
+
+	folder, err := h.Folders.Update(r.Context(), userID, folderID, func(folder *models.Folder) error {
+		if name != nil {
+			folder.Name = *name
+		}
+		if _, ok := patch["parent_id"]; ok {
+			folder.ParentID = parentID
+		}
+		return nil
+	})
+	if writeFolderUpdateError(w, err, "Gagal mengupdate folder") {
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	utils.WriteSuccess(w, "Folder berhasil diupdate", folder)
+}
+
+// DeleteFolder memindahkan folder beserta catatan di dalamnya ke trash (soft delete).
+// Query mode=reparent (default) memindahkan sub-folder ke parent folder yang dihapus,
+// mode=cascade ikut memindahkan seluruh sub-folder dan catatannya ke trash.
+func (h *Handler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	folderID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = "reparent"
+	}
+	if mode != "reparent" && mode != "cascade" {
+		utils.WriteError(w, http.StatusBadRequest, "Parameter mode harus reparent atau cascade")
+		return
+	}
+
+	deleted, err := h.Folders.Delete(r.Context(), userID, folderID, mode == "cascade")
+	if errors.Is(err, store.ErrNotFound) {
 		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan")
 		return
 	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal menghapus folder")
+		return
+	}
 
-	utils.WriteSuccess(w, "Folder berhasil dihapus", nil)
+	utils.WriteSuccess(w, "Folder berhasil dipindahkan ke trash", map[string]interface{}{
+		"deleted_folders": deleted,
+	})
+}
+
+// writeFolderUpdateError memetakan error dari FolderStore.Update ke response HTTP.
+// Return false jika err nil.
+func writeFolderUpdateError(w http.ResponseWriter, err error, message string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, store.ErrNotFound):
+		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan")
+	case errors.Is(err, store.ErrFolderNotFound):
+		utils.WriteError(w, http.StatusNotFound, "Parent folder tidak ditemukan")
+	case errors.Is(err, store.ErrFolderCycle):
+		utils.WriteError(w, http.StatusConflict, "Folder tidak bisa dipindah ke dalam dirinya sendiri atau sub-foldernya")
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+	return true
 }
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"notes-api/internal/mail"
+	"notes-api/internal/oidc"
+	"notes-api/internal/ratelimit"
+	"notes-api/internal/store"
+)
+
+// Handler berisi repository dan layanan yang dipakai semua handler HTTP. Handler tidak tahu
+// database apa yang dipakai, jadi bisa dijalankan dengan store.Store apa pun
+// (MySQL di production, memory untuk test).
+type Handler struct {
+	Users     store.UserStore
+	Notes     store.NoteStore
+	Folders   store.FolderStore
+	Tags      store.TagStore
+	Revisions store.RevisionStore
+	Trash     store.TrashStore
+	Tokens    store.TokenStore
+
+	Mailer     mail.Mailer             // pengirim email verifikasi dan reset password
+	LoginLimit *ratelimit.LoginLimiter // rate limit login password dan kode 2FA
+	OIDC       *oidc.Provider          // nil berarti login SSO tidak aktif
+}
+
+// New membuat Handler dari kumpulan repository. Email hanya di-log, rate limit login
+// disimpan di memory, dan SSO tidak aktif; ganti field-nya untuk konfigurasi lain.
+func New(st *store.Store) *Handler {
+	return &Handler{
+		Users:      st.Users,
+		Notes:      st.Notes,
+		Folders:    st.Folders,
+		Tags:       st.Tags,
+		Revisions:  st.Revisions,
+		Trash:      st.Trash,
+		Tokens:     st.Tokens,
+		Mailer:     mail.LogMailer{},
+		LoginLimit: ratelimit.NewLoginLimiter(ratelimit.NewMemoryStore()),
+	}
+}
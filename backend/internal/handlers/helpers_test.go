@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notes-api/internal/middleware"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/store/memory"
+	"notes-api/internal/store/storetest"
+	"notes-api/internal/utils"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// listResponse adalah bentuk response endpoint list yang dipakai di test
+type listResponse struct {
+	Success    bool          `json:"success"`
+	Message    string        `json:"message"`
+	Data       []models.Note `json:"data"`
+	NextCursor *string       `json:"next_cursor"`
+}
+
+// doAs mengirim request ke handler sebagai user yang sudah login (tanpa middleware Auth).
+// body di-encode ke JSON jika tidak nil.
+func doAs(t *testing.T, h http.Handler, userID int, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	return serve(h, newRequestAs(t, userID, method, path, body))
+}
+
+// newRequestAs membuat request JSON dengan user ID di context, sama seperti setelah middleware Auth.
+// userID 0 berarti request tanpa login.
+func newRequestAs(t *testing.T, userID int, method, path string, body interface{}) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if userID != 0 {
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	}
+	return req
+}
+
+// serve menjalankan request ke handler dan mengembalikan response-nya
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// testStores adalah backend yang dipakai test API: in-memory dan SQLite :memory:.
+// Keduanya tidak butuh server database, jadi selalu dijalankan.
+var testStores = []struct {
+	name string
+	open func(t testing.TB) *store.Store
+}{
+	{"memory", func(testing.TB) *store.Store { return memory.New() }},
+	{"sqlite", storetest.OpenSQLite},
+}
+
+// forEachStore menjalankan fn sebagai subtest untuk setiap backend di testStores. Setiap
+// subtest mendapat store kosong dan router dengan semua endpoint serta middleware-nya.
+func forEachStore(t *testing.T, fn func(t *testing.T, api http.Handler, st *store.Store)) {
+	for _, backend := range testStores {
+		open := backend.open
+		t.Run(backend.name, func(t *testing.T) {
+			st := open(t)
+			r := chi.NewRouter()
+			New(st).Routes(r)
+			fn(t, r, st)
+		})
+	}
+}
+
+// doWithToken mengirim request lewat middleware Auth dengan header Authorization: Bearer token.
+// token kosong berarti tanpa header Authorization.
+func doWithToken(t *testing.T, h http.Handler, token, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	req := newRequestAs(t, 0, method, path, body)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return serve(h, req)
+}
+
+// accessToken membuat access token JWT untuk user tanpa lewat login (bcrypt lambat)
+func accessToken(t *testing.T, st *store.Store, userID int) string {
+	t.Helper()
+	user, err := st.Users.Get(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := utils.GenerateToken(user.ID, user.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// decode membaca body response JSON ke v
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("response bukan JSON (%v): %s", err, rec.Body.String())
+	}
+}
+
+// seedUser membuat user dengan email terverifikasi lalu mengembalikan ID-nya
+func seedUser(t *testing.T, st *store.Store, username string) int {
+	t.Helper()
+	verifiedAt := time.Now().UTC()
+	user := models.User{Username: username, Email: username + "@example.com", PasswordHash: "x", EmailVerifiedAt: &verifiedAt}
+	if err := st.Users.Create(context.Background(), &user); err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
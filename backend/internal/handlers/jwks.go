@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"notes-api/internal/utils"
+)
+
+// JWKS mengembalikan public key JWT dalam format JSON Web Key Set.
+// Response tidak dibungkus utils.WriteSuccess karena formatnya ditentukan RFC 7517.
+func JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(utils.PublicJWKS())
+}
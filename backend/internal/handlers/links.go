@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/url"
+	"os"
+	"strings"
+)
+
+// apiLink membuat URL absolut ke endpoint API ini (base URL dari env APP_URL)
+func apiLink(path string, query url.Values) string {
+	base := os.Getenv("APP_URL")
+	if base == "" {
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = "8080"
+		}
+		base = "http://localhost:" + port
+	}
+	return buildLink(base, path, query)
+}
+
+// frontendLink membuat URL absolut ke halaman frontend (base URL dari env FRONTEND_URL)
+func frontendLink(path string, query url.Values) string {
+	base := os.Getenv("FRONTEND_URL")
+	if base == "" {
+		base = "http://localhost:5173"
+	}
+	return buildLink(base, path, query)
+}
+
+func buildLink(base, path string, query url.Values) string {
+	link := strings.TrimRight(base, "/") + path
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
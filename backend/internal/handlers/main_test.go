@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"log"
+	"notes-api/internal/utils"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Access token di test ditandatangani dengan secret HS256 tetap
+	os.Setenv("JWT_SECRET", "test-secret-yang-panjangnya-minimal-32-byte")
+	if err := utils.LoadKeys(); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(m.Run())
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"notes-api/internal/mail"
+	"notes-api/internal/middleware"
+	"notes-api/internal/models"
+	"notes-api/internal/revocation"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"strings"
+	"time"
+)
+
+// Masa berlaku link konfirmasi perubahan email
+const emailChangeTTL = 24 * time.Hour
+
+// GetMe mengambil data user yang sedang login
+func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	user, err := h.Users.Get(r.Context(), userID)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "User tidak ditemukan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data user")
+		return
+	}
+
+	utils.WriteSuccess(w, "Data user berhasil diambil", user)
+}
+
+// UpdateMe mengubah username, full_name dan email user yang sedang login.
+// Email baru tidak langsung dipakai: link konfirmasi dikirim ke alamat baru dulu.
+func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Username == "" || req.Email == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Username dan email wajib diisi")
+		return
+	}
+
+	user, err := h.Users.Get(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data user")
+		return
+	}
+
+	// Cek keunikan username dan email terhadap user lain
+	taken, err := h.Users.UsernameTaken(r.Context(), req.Username, userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengupdate profil")
+		return
+	}
+	if taken {
+		utils.WriteError(w, http.StatusConflict, "Username sudah digunakan")
+		return
+	}
+
+	emailChanged := !strings.EqualFold(req.Email, user.Email)
+	if emailChanged {
+		taken, err := h.Users.EmailTaken(r.Context(), req.Email, userID)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, "Gagal mengupdate profil")
+			return
+		}
+		if taken {
+			utils.WriteError(w, http.StatusConflict, "Email sudah digunakan")
+			return
+		}
+	}
+
+	err = h.Users.UpdateProfile(r.Context(), userID, req.Username, req.FullName)
+	if errors.Is(err, store.ErrDuplicate) {
+		utils.WriteError(w, http.StatusConflict, "Username sudah digunakan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengupdate profil")
+		return
+	}
+
+	user.Username = req.Username
+	user.FullName = req.FullName
+
+	if !emailChanged {
+		utils.WriteSuccess(w, "Profil berhasil diupdate", user)
+		return
+	}
+
+	if err := h.requestEmailChange(r.Context(), userID, req.Email); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengirim konfirmasi email")
+		return
+	}
+
+	utils.WriteSuccess(w, "Profil berhasil diupdate, cek email baru untuk konfirmasi perubahan email", map[string]interface{}{
+		"user":          user,
+		"pending_email": req.Email,
+	})
+}
+
+// ConfirmEmailChange menerapkan email baru setelah user membuka link konfirmasi
+func (h *Handler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Token wajib diisi")
+		return
+	}
+
+	// Token hanya bisa dipakai sekali. Membuka link di alamat baru sekaligus
+	// membuktikan email itu milik user, jadi email baru langsung terverifikasi.
+	newEmail, err := h.Users.ConfirmEmailChange(r.Context(), utils.HashToken(token))
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		utils.WriteError(w, http.StatusBadRequest, "Link konfirmasi tidak valid atau sudah dipakai")
+		return
+	case errors.Is(err, store.ErrExpired):
+		utils.WriteError(w, http.StatusBadRequest, "Link konfirmasi sudah kedaluwarsa")
+		return
+	case errors.Is(err, store.ErrDuplicate):
+		utils.WriteError(w, http.StatusConflict, "Email sudah digunakan")
+		return
+	case err != nil:
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengkonfirmasi email")
+		return
+	}
+
+	utils.WriteSuccess(w, "Email berhasil diubah", map[string]interface{}{
+		"email": newEmail,
+	})
+}
+
+// ChangePassword mengganti password setelah memverifikasi password saat ini.
+// Semua token lama dicabut, lalu token baru dikembalikan supaya sesi ini tetap login.
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	if req.CurrentPassword == "" || strings.TrimSpace(req.NewPassword) == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Password saat ini dan password baru wajib diisi")
+		return
+	}
+
+	user, err := h.Users.Get(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data user")
+		return
+	}
+
+	if !utils.CheckPassword(req.CurrentPassword, user.PasswordHash) {
+		utils.WriteError(w, http.StatusUnauthorized, "Password saat ini salah")
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses password")
+		return
+	}
+
+	if err := h.Users.SetPassword(r.Context(), userID, hashedPassword); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengganti password")
+		return
+	}
+
+	// Token yang mungkin dicuri dengan password lama tidak berlaku lagi, termasuk token yang
+	// sedang dipakai jika di-issue di detik yang sama
+	if err := h.Tokens.RevokeAllForUser(r.Context(), userID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mencabut sesi lama")
+		return
+	}
+	if err := revocation.Revoke(r.Context(), h.Tokens, middleware.GetClaims(r)); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mencabut sesi lama")
+		return
+	}
+
+	resp, err := h.issueTokens(r.Context(), user)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat token")
+		return
+	}
+
+	utils.WriteSuccess(w, "Password berhasil diganti", resp)
+}
+
+// requestEmailChange menyimpan permintaan ganti email dan mengirim link konfirmasi ke alamat baru
+func (h *Handler) requestEmailChange(ctx context.Context, userID int, newEmail string) error {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	// Hanya permintaan terakhir yang berlaku
+	if err := h.Users.RequestEmailChange(ctx, userID, newEmail, hash, time.Now().UTC().Add(emailChangeTTL)); err != nil {
+		return err
+	}
+
+	link := apiLink("/api/email/confirm", url.Values{"token": {token}})
+	return h.Mailer.Send(mail.Message{
+		To:      newEmail,
+		Subject: "Konfirmasi email baru Notes",
+		Body: "Buka link berikut untuk memakai alamat ini sebagai email akun Notes kamu (berlaku 24 jam):\n" + link + "\n\n" +
+			"Abaikan email ini jika kamu tidak mengubah email.",
+	})
+}
@@ -1,12 +1,12 @@
 package handlers
 
 import (
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"notes-api/internal/database"
 	"notes-api/internal/middleware"
 	"notes-api/internal/models"
+	"notes-api/internal/store"
 	"notes-api/internal/utils"
 	"strconv"
 	"strings"
@@ -14,84 +14,37 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-// GetNotes mengambil semua catatan milik user
-func GetNotes(w http.ResponseWriter, r *http.Request) {
-	userID := middleware.GetUserID(r)
-
-	// Optional: filter by folder_id atau is_favorite
-	folderID := r.URL.Query().Get("folder_id")
-	favorite := r.URL.Query().Get("favorite")
-	search := r.URL.Query().Get("search")
-
-	query := "SELECT n.id, n.user_id, n.folder_id, f.name as folder_name, n.title, n.content, n.is_favorite, n.created_at, n.updated_at FROM notes n LEFT JOIN folders f ON n.folder_id = f.id AND f.user_id = n.user_id WHERE n.user_id = ?"
-	args := []interface{}{userID}
-
-	if folderID != "" {
-		query += " AND folder_id = ?"
-		args = append(args, folderID)
-	}
-
-	if favorite == "true" {
-		query += " AND is_favorite = true"
-	}
-
-	if search != "" {
-		query += " AND (title LIKE ? OR content LIKE ?)"
-		searchPattern := "%" + search + "%"
-		args = append(args, searchPattern, searchPattern)
-	}
-
-	query += " ORDER BY created_at DESC"
-
-	rows, err := database.DB.Query(query, args...)
+// GetNotes mengambil catatan milik user per halaman (cursor pagination)
+func (h *Handler) GetNotes(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageParams(r)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data catatan")
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	defer rows.Close()
 
-	notes := []models.Note{}
-	for rows.Next() {
-		var note models.Note
-		var folderID sql.NullInt64
-		var folderName sql.NullString
-		err := rows.Scan(&note.ID, &note.UserID, &folderID, &folderName, &note.Title, &note.Content, &note.IsFavorite, &note.CreatedAt, &note.UpdatedAt)
+	// Optional: filter by folder_id atau is_favorite
+	var filter store.NoteFilter
+	if folderID := r.URL.Query().Get("folder_id"); folderID != "" {
+		id, err := strconv.Atoi(folderID)
 		if err != nil {
-			continue
-		}
-
-		if folderID.Valid {
-			fid := int(folderID.Int64)
-			note.FolderID = &fid
-		}
-		if folderName.Valid {
-			note.FolderName = folderName.String
-		}
-
-		// Ambil tags untuk note ini
-		tags, err := getTagsForNote(note.ID, userID)
-		if err == nil {
-			note.Tags = tags
+			utils.WriteError(w, http.StatusBadRequest, "Parameter folder_id tidak valid")
+			return
 		}
-
-		notes = append(notes, note)
+		filter.FolderIDs = []int{id}
 	}
+	filter.Favorite = r.URL.Query().Get("favorite") == "true"
+	filter.Search = r.URL.Query().Get("search")
 
-	utils.WriteSuccess(w, "Data catatan berhasil diambil", notes)
+	h.writeNotesPage(w, r, filter, page)
 }
 
 // GetNoteByID mengambil detail satu catatan
-func GetNoteByID(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetNoteByID(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	noteID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	var note models.Note
-	var folderID sql.NullInt64
-
-	query := "SELECT id, user_id, folder_id, title, content, is_favorite, created_at, updated_at FROM notes WHERE id = ? AND user_id = ?"
-	err := database.DB.QueryRow(query, noteID, userID).Scan(&note.ID, &note.UserID, &folderID, &note.Title, &note.Content, &note.IsFavorite, &note.CreatedAt, &note.UpdatedAt)
-
-	if err == sql.ErrNoRows {
+	note, err := h.Notes.Get(r.Context(), userID, noteID)
+	if errors.Is(err, store.ErrNotFound) {
 		utils.WriteError(w, http.StatusNotFound, "Catatan tidak ditemukan")
 		return
 	}
@@ -100,135 +53,79 @@ func GetNoteByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if folderID.Valid {
-		fid := int(folderID.Int64)
-		note.FolderID = &fid
+	// Client yang sudah punya versi terbaru cukup dapat 304
+	etag := noteETag(note)
+	w.Header().Set("ETag", etag)
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
 
 	// Ambil tags untuk note ini
-	tags, err := getTagsForNote(noteID, userID)
-	if err == nil {
-		note.Tags = tags
+	note.Tags = []models.Tag{}
+	if tagsByNote, err := h.Tags.ForNotes(r.Context(), userID, []int{noteID}); err == nil && tagsByNote[noteID] != nil {
+		note.Tags = tagsByNote[noteID]
 	}
 
 	utils.WriteSuccess(w, "Data catatan berhasil diambil", note)
 }
 
-// GetNotesByFolder mengambil semua catatan dalam folder tertentu
-func GetNotesByFolder(w http.ResponseWriter, r *http.Request) {
+// GetNotesByFolder mengambil catatan dalam folder tertentu per halaman.
+// Dengan recursive=true, catatan di semua sub-folder ikut diambil.
+func (h *Handler) GetNotesByFolder(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	folderID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	// Verifikasi bahwa folder milik user
-	var count int
-	err := database.DB.QueryRow("SELECT COUNT(*) FROM folders WHERE id = ? AND user_id = ?", folderID, userID).Scan(&count)
-	if err != nil || count == 0 {
-		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan")
+	page, err := parsePageParams(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Ambil catatan dalam folder
-	query := "SELECT n.id, n.user_id, n.folder_id, f.name as folder_name, n.title, n.content, n.is_favorite, n.created_at, n.updated_at FROM notes n LEFT JOIN folders f ON n.folder_id = f.id AND f.user_id = n.user_id WHERE n.user_id = ? AND n.folder_id = ? ORDER BY n.created_at DESC"
-	rows, err := database.DB.Query(query, userID, folderID)
+	// Verifikasi bahwa folder milik user
+	children, err := h.Folders.Children(r.Context(), userID)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data catatan")
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data folder")
+		return
+	}
+	if _, ok := children[folderID]; !ok {
+		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan")
 		return
 	}
-	defer rows.Close()
-
-	notes := []models.Note{}
-	for rows.Next() {
-		var note models.Note
-		var folderID sql.NullInt64
-		var folderName sql.NullString
-		err := rows.Scan(&note.ID, &note.UserID, &folderID, &folderName, &note.Title, &note.Content, &note.IsFavorite, &note.CreatedAt, &note.UpdatedAt)
-		if err != nil {
-			continue
-		}
-
-		if folderID.Valid {
-			fid := int(folderID.Int64)
-			note.FolderID = &fid
-		}
-		if folderName.Valid {
-			note.FolderName = folderName.String
-		}
-
-		// Ambil tags untuk note ini
-		tags, err := getTagsForNote(note.ID, userID)
-		if err == nil {
-			note.Tags = tags
-		}
 
-		notes = append(notes, note)
+	folderIDs := []int{folderID}
+	if r.URL.Query().Get("recursive") == "true" {
+		folderIDs = store.SubtreeIDs(children, folderID)
 	}
 
-	utils.WriteSuccess(w, "Data catatan berhasil diambil", notes)
+	h.writeNotesPage(w, r, store.NoteFilter{FolderIDs: folderIDs}, page)
 }
 
-// GetNotesByTag mengambil semua catatan yang memiliki tag tertentu
-func GetNotesByTag(w http.ResponseWriter, r *http.Request) {
+// GetNotesByTag mengambil catatan yang memiliki tag tertentu per halaman
+func (h *Handler) GetNotesByTag(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	tagID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	// Cek apakah tag milik user
-	var count int
-	checkTag := "SELECT COUNT(*) FROM tags WHERE id = ? AND user_id = ?"
-	database.DB.QueryRow(checkTag, tagID, userID).Scan(&count)
-	if count == 0 {
-		utils.WriteError(w, http.StatusNotFound, "Tag tidak ditemukan")
-		return
-	}
-
-	// Query notes yang memiliki tag ini
-	query := `
-		SELECT DISTINCT n.id, n.user_id, n.folder_id, f.name as folder_name, n.title, n.content, n.is_favorite, n.created_at, n.updated_at 
-		FROM notes n 
-		LEFT JOIN folders f ON n.folder_id = f.id AND f.user_id = n.user_id
-		INNER JOIN note_tags nt ON n.id = nt.note_id 
-		WHERE nt.tag_id = ? AND n.user_id = ?
-		ORDER BY n.created_at DESC
-	`
-
-	rows, err := database.DB.Query(query, tagID, userID)
+	page, err := parsePageParams(r)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data catatan")
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	defer rows.Close()
-
-	notes := []models.Note{}
-	for rows.Next() {
-		var note models.Note
-		var folderID sql.NullInt64
-		var folderName sql.NullString
-		err := rows.Scan(&note.ID, &note.UserID, &folderID, &folderName, &note.Title, &note.Content, &note.IsFavorite, &note.CreatedAt, &note.UpdatedAt)
-		if err != nil {
-			continue
-		}
-
-		if folderID.Valid {
-			fid := int(folderID.Int64)
-			note.FolderID = &fid
-		}
-		if folderName.Valid {
-			note.FolderName = folderName.String
-		}
-
-		// Ambil tags untuk note ini
-		tags, err := getTagsForNote(note.ID, userID)
-		if err == nil {
-			note.Tags = tags
-		}
 
-		notes = append(notes, note)
+	// Cek apakah tag milik user
+	if _, err := h.Tags.Get(r.Context(), userID, tagID); errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Tag tidak ditemukan")
+		return
+	} else if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data tag")
+		return
 	}
 
-	utils.WriteSuccess(w, "Data catatan berhasil diambil", notes)
+	h.writeNotesPage(w, r, store.NoteFilter{TagID: tagID}, page)
 }
 
 // CreateNote membuat catatan baru
-func CreateNote(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CreateNote(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 
 	var note models.Note
@@ -242,97 +139,201 @@ func CreateNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := "INSERT INTO notes (user_id, folder_id, title, content, is_favorite) VALUES (?, ?, ?, ?, ?)"
-	result, err := database.DB.Exec(query, userID, note.FolderID, note.Title, note.Content, note.IsFavorite)
+	note.UserID = userID
+	err := h.Notes.Create(r.Context(), &note)
+	if errors.Is(err, store.ErrFolderNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan")
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat catatan")
 		return
 	}
 
-	noteID, _ := result.LastInsertId()
-	note.ID = int(noteID)
-	note.UserID = userID
-
+	w.Header().Set("ETag", noteETag(note))
 	utils.WriteSuccess(w, "Catatan berhasil dibuat", note)
 }
 
 // UpdateNote mengupdate catatan
-func UpdateNote(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateNote(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	noteID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	var note models.Note
-	if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+	var input models.Note
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
 		return
 	}
 
-	if strings.TrimSpace(note.Title) == "" {
+	if strings.TrimSpace(input.Title) == "" {
 		utils.WriteError(w, http.StatusBadRequest, "Judul catatan wajib diisi")
 		return
 	}
 
-	query := "UPDATE notes SET folder_id = ?, title = ?, content = ?, is_favorite = ? WHERE id = ? AND user_id = ?"
-	result, err := database.DB.Exec(query, note.FolderID, note.Title, note.Content, note.IsFavorite, noteID, userID)
+	// Isi lama dicek terhadap If-Match; store menyimpannya sebagai revisi jika judul/isi berubah
+	updated, err := h.Notes.Update(r.Context(), userID, noteID, func(note *models.Note) error {
+		if err := checkIfMatch(r, *note); err != nil {
+			return err
+		}
+		note.FolderID = input.FolderID
+		note.Title = input.Title
+		note.Content = input.Content
+		note.IsFavorite = input.IsFavorite
+		return nil
+	})
+	if writeNoteWriteError(w, err, "Gagal mengupdate catatan") {
+		return
+	}
+
+	w.Header().Set("ETag", noteETag(updated))
+	utils.WriteSuccess(w, "Catatan berhasil diupdate", nil)
+}
+
+// PatchNote mengupdate sebagian field catatan mengikuti JSON Merge Patch (RFC 7396).
+// Field yang tidak dikirim tidak berubah, null mengosongkan field (folder_id, content).
+func (h *Handler) PatchNote(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	noteID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	patch, err := decodeMergePatch(r)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengupdate catatan")
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		utils.WriteError(w, http.StatusNotFound, "Catatan tidak ditemukan")
+	// Validasi isi patch dulu supaya catatan tidak perlu dikunci untuk request yang salah
+	var title, content *string
+	var favorite *bool
+	var folderID *int
+	if raw, ok := patch["title"]; ok {
+		if err := json.Unmarshal(raw, &title); err != nil || title == nil || strings.TrimSpace(*title) == "" {
+			utils.WriteError(w, http.StatusBadRequest, "Judul catatan wajib diisi")
+			return
+		}
+	}
+	if raw, ok := patch["content"]; ok {
+		if err := json.Unmarshal(raw, &content); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Field content tidak valid")
+			return
+		}
+	}
+	if raw, ok := patch["is_favorite"]; ok {
+		if err := json.Unmarshal(raw, &favorite); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Field is_favorite tidak valid")
+			return
+		}
+	}
+	if raw, ok := patch["folder_id"]; ok {
+		if err := json.Unmarshal(raw, &folderID); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Field folder_id tidak valid")
+			return
+		}
+	}
+
+	updated, err := h.Notes.Update(r.Context(), userID, noteID, func(note *models.Note) error {
+		if err := checkIfMatch(r, *note); err != nil {
+			return err
+		}
+		if _, ok := patch["title"]; ok {
+			note.Title = *title
+		}
+		if _, ok := patch["content"]; ok {
+			note.Content = ""
+			if content != nil {
+				note.Content = *content
+			}
+		}
+		if _, ok := patch["is_favorite"]; ok {
+			note.IsFavorite = favorite != nil && *favorite
+		}
+		if _, ok := patch["folder_id"]; ok {
+			note.FolderID = folderID
+		}
+		return nil
+	})
+	if writeNoteWriteError(w, err, "Gagal mengupdate catatan") {
 		return
 	}
 
-	utils.WriteSuccess(w, "Catatan berhasil diupdate", nil)
+	w.Header().Set("ETag", noteETag(updated))
+	utils.WriteSuccess(w, "Catatan berhasil diupdate", updated)
 }
 
-// DeleteNote menghapus catatan
-func DeleteNote(w http.ResponseWriter, r *http.Request) {
+// DeleteNote memindahkan catatan ke trash (soft delete)
+func (h *Handler) DeleteNote(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	noteID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	query := "DELETE FROM notes WHERE id = ? AND user_id = ?"
-	result, err := database.DB.Exec(query, noteID, userID)
-	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal menghapus catatan")
+	err := h.Notes.Delete(r.Context(), userID, noteID, func(current models.Note) error {
+		return checkIfMatch(r, current)
+	})
+	if writeNoteWriteError(w, err, "Gagal menghapus catatan") {
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	utils.WriteSuccess(w, "Catatan berhasil dipindahkan ke trash", nil)
+}
+
+// writeNoteWriteError memetakan error dari NoteStore.Update/Delete ke response HTTP.
+// Return false jika err nil.
+func writeNoteWriteError(w http.ResponseWriter, err error, message string) bool {
+	var precondition *preconditionError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &precondition):
+		writePreconditionError(w, precondition)
+	case errors.Is(err, store.ErrNotFound):
 		utils.WriteError(w, http.StatusNotFound, "Catatan tidak ditemukan")
+	case errors.Is(err, store.ErrFolderNotFound):
+		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan")
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, message)
+	}
+	return true
+}
+
+// writeNotesPage mengambil satu halaman catatan sesuai filter lalu menulis response-nya
+func (h *Handler) writeNotesPage(w http.ResponseWriter, r *http.Request, filter store.NoteFilter, page pageParams) {
+	userID := middleware.GetUserID(r)
+
+	sp, err := storePage(page)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Parameter cursor tidak valid")
+		return
+	}
+
+	notes, err := h.Notes.List(r.Context(), userID, filter, sp)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data catatan")
+		return
+	}
+
+	notes, nextCursor := trimPage(notes, page)
+
+	// Ambil tags untuk semua catatan di halaman ini dalam satu query
+	if err := h.attachTags(r, notes); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data tag catatan")
 		return
 	}
 
-	utils.WriteSuccess(w, "Catatan berhasil dihapus", nil)
+	utils.WritePaginated(w, "Data catatan berhasil diambil", notes, nextCursor)
 }
 
-// getTagsForNote helper function untuk mengambil tags dari sebuah note
-func getTagsForNote(noteID, userID int) ([]models.Tag, error) {
-	query := `
-		SELECT t.id, t.user_id, t.name, t.created_at 
-		FROM tags t 
-		INNER JOIN note_tags nt ON t.id = nt.tag_id 
-		WHERE nt.note_id = ? AND t.user_id = ?
-		ORDER BY t.name ASC
-	`
-
-	rows, err := database.DB.Query(query, noteID, userID)
-	if err != nil {
-		return nil, err
+// attachTags mengisi field Tags pada setiap note memakai TagStore.ForNotes
+func (h *Handler) attachTags(r *http.Request, notes []models.Note) error {
+	ids := make([]int, len(notes))
+	for i, note := range notes {
+		ids[i] = note.ID
 	}
-	defer rows.Close()
 
-	tags := []models.Tag{}
-	for rows.Next() {
-		var tag models.Tag
-		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt); err != nil {
-			continue
-		}
-		tags = append(tags, tag)
+	tagsByNote, err := h.Tags.ForNotes(r.Context(), middleware.GetUserID(r), ids)
+	if err != nil {
+		return err
 	}
 
-	return tags, nil
+	for i := range notes {
+		notes[i].Tags = tagsByNote[notes[i].ID]
+	}
+	return nil
 }
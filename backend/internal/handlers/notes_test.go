@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/store/storetest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// seedNotes membuat satu folder dan dua tag untuk user, lalu n catatan di folder itu
+// yang masing-masing memakai kedua tag
+func seedNotes(t *testing.T, st *store.Store, userID, n int) (folderID, tagID int) {
+	t.Helper()
+	ctx := context.Background()
+
+	folder := models.Folder{UserID: userID, Name: "Kerja"}
+	if err := st.Folders.Create(ctx, &folder); err != nil {
+		t.Fatal(err)
+	}
+
+	tags := []models.Tag{{UserID: userID, Name: "penting"}, {UserID: userID, Name: "rapat"}}
+	for i := range tags {
+		if err := st.Tags.Create(ctx, &tags[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		note := models.Note{UserID: userID, FolderID: &folder.ID, Title: fmt.Sprintf("Catatan %d", i), Content: "isi"}
+		if err := st.Notes.Create(ctx, &note); err != nil {
+			t.Fatal(err)
+		}
+		for _, tag := range tags {
+			if err := st.Tags.Attach(ctx, userID, note.ID, tag.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return folder.ID, tags[0].ID
+}
+
+// Endpoint list catatan harus menjalankan jumlah query yang sama berapa pun jumlah
+// catatannya: tag dimuat sekaligus untuk satu halaman, bukan satu query per catatan.
+func TestNoteListsQueryCountIndependentOfSize(t *testing.T) {
+	st, counter := storetest.OpenCountingSQLite(t)
+	h := New(st)
+
+	r := chi.NewRouter()
+	r.Get("/api/notes", h.GetNotes)
+	r.Get("/api/folders/{id}/notes", h.GetNotesByFolder)
+	r.Get("/api/tags/{id}/notes", h.GetNotesByTag)
+
+	type account struct {
+		userID, folderID, tagID, notes int
+	}
+	small := account{userID: seedUser(t, st, "sedikit"), notes: 1}
+	small.folderID, small.tagID = seedNotes(t, st, small.userID, small.notes)
+	large := account{userID: seedUser(t, st, "banyak"), notes: 40}
+	large.folderID, large.tagID = seedNotes(t, st, large.userID, large.notes)
+
+	endpoints := []struct {
+		name string
+		path func(a account) string
+	}{
+		{"GetNotes", func(a account) string { return "/api/notes" }},
+		{"GetNotesByFolder", func(a account) string { return fmt.Sprintf("/api/folders/%d/notes", a.folderID) }},
+		{"GetNotesByTag", func(a account) string { return fmt.Sprintf("/api/tags/%d/notes", a.tagID) }},
+	}
+
+	for _, ep := range endpoints {
+		t.Run(ep.name, func(t *testing.T) {
+			queries := func(a account) int {
+				counter.Reset()
+				rec := doAs(t, r, a.userID, http.MethodGet, ep.path(a), nil)
+				n := counter.Count()
+
+				if rec.Code != http.StatusOK {
+					t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+				}
+				var resp listResponse
+				decode(t, rec, &resp)
+				if len(resp.Data) != a.notes {
+					t.Fatalf("dapat %d catatan, seharusnya %d", len(resp.Data), a.notes)
+				}
+				for _, note := range resp.Data {
+					if len(note.Tags) != 2 {
+						t.Fatalf("catatan %d punya %d tag, seharusnya 2", note.ID, len(note.Tags))
+					}
+				}
+				return n
+			}
+
+			one, many := queries(small), queries(large)
+			if one != many {
+				t.Errorf("1 catatan butuh %d query, %d catatan butuh %d query", one, large.notes, many)
+			}
+		})
+	}
+}
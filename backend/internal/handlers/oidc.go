@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"notes-api/internal/models"
+	"notes-api/internal/oidc"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Masa berlaku state login OIDC (waktu user berada di halaman login provider)
+const oidcStateTTL = 10 * time.Minute
+
+// oidcStateCookie menyimpan hash state di browser yang memulai login, supaya callback
+// dengan state milik orang lain (login CSRF) ditolak
+const oidcStateCookie = "oidc_state"
+
+var (
+	errOIDCEmailUnverified = errors.New("email dari identity provider belum terverifikasi")
+	errOIDCEmailConflict   = errors.New("email sudah terdaftar sebagai akun lokal yang belum terverifikasi")
+)
+
+// OIDCAuthorize memulai login SSO: membuat state, nonce dan PKCE verifier, lalu
+// mengembalikan URL halaman login identity provider untuk dibuka frontend
+func (h *Handler) OIDCAuthorize(w http.ResponseWriter, r *http.Request) {
+	provider := h.OIDC
+	if provider == nil {
+		utils.WriteError(w, http.StatusNotFound, "Login SSO tidak diaktifkan")
+		return
+	}
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memulai login SSO")
+		return
+	}
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memulai login SSO")
+		return
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memulai login SSO")
+		return
+	}
+
+	authURL, err := provider.AuthCodeURL(r.Context(), state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		log.Println("OIDC:", err)
+		utils.WriteError(w, http.StatusBadGateway, "Identity provider tidak bisa dihubungi")
+		return
+	}
+
+	err = h.Tokens.CreateOIDCState(r.Context(), store.OIDCState{
+		StateHash:    utils.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().UTC().Add(oidcStateTTL),
+	})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memulai login SSO")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    utils.HashToken(state),
+		Path:     "/api/oidc",
+		MaxAge:   int(oidcStateTTL / time.Second),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(provider.Config.RedirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	utils.WriteSuccess(w, "Buka authorization_url untuk login SSO", map[string]interface{}{
+		"authorization_url": authURL,
+	})
+}
+
+// OIDCCallback menyelesaikan login SSO: menukar code dengan ID token, memvalidasinya,
+// lalu login ke user yang terhubung (atau menghubungkan / membuat user baru)
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := h.OIDC
+	if provider == nil {
+		utils.WriteError(w, http.StatusNotFound, "Login SSO tidak diaktifkan")
+		return
+	}
+
+	var req models.OIDCCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+	if req.Code == "" || req.State == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Code dan state wajib diisi")
+		return
+	}
+
+	// State harus berasal dari browser yang sama dengan yang memanggil authorize
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(utils.HashToken(req.State))) != 1 {
+		utils.WriteError(w, http.StatusBadRequest, "Sesi login SSO tidak valid atau sudah kedaluwarsa, silakan ulangi")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: "/api/oidc", MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteLaxMode})
+
+	// State hanya bisa dipakai sekali
+	state, err := h.Tokens.ConsumeOIDCState(r.Context(), utils.HashToken(req.State))
+	if errors.Is(err, store.ErrNotFound) || (err == nil && time.Now().After(state.ExpiresAt)) {
+		utils.WriteError(w, http.StatusBadRequest, "Sesi login SSO tidak valid atau sudah kedaluwarsa, silakan ulangi")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses login SSO")
+		return
+	}
+
+	rawIDToken, err := provider.Exchange(r.Context(), req.Code, state.CodeVerifier)
+	if err != nil {
+		log.Println("OIDC:", err)
+		utils.WriteError(w, http.StatusUnauthorized, "Login SSO gagal")
+		return
+	}
+
+	claims, err := provider.VerifyIDToken(r.Context(), rawIDToken, state.Nonce)
+	if err != nil {
+		log.Println("OIDC:", err)
+		utils.WriteError(w, http.StatusUnauthorized, "Login SSO gagal")
+		return
+	}
+
+	identity, err := provider.Config.Claims.MapClaims(claims)
+	if err != nil {
+		log.Println("OIDC:", err)
+		utils.WriteError(w, http.StatusUnauthorized, "Login SSO gagal")
+		return
+	}
+
+	user, err := h.findOrCreateOIDCUser(r.Context(), identity)
+	if err == errOIDCEmailUnverified {
+		utils.WriteError(w, http.StatusForbidden, "Email akun SSO belum terverifikasi di identity provider")
+		return
+	}
+	if err == errOIDCEmailConflict {
+		utils.WriteError(w, http.StatusConflict, "Email ini sudah terdaftar tapi belum diverifikasi; verifikasi dulu lalu login SSO lagi")
+		return
+	}
+	if err != nil {
+		log.Println("OIDC:", err)
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses login SSO")
+		return
+	}
+
+	if user.DisabledAt != nil {
+		utils.WriteError(w, http.StatusForbidden, accountDisabledMessage)
+		return
+	}
+
+	// SSO tidak melewati 2FA: sama seperti login password, token baru diberikan setelah
+	// kode 2FA dikirim ke /api/login/2fa
+	if user.TwoFactorEnabled {
+		challenge, err := h.createLoginChallenge(r.Context(), user.ID)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses login SSO")
+			return
+		}
+		utils.WriteSuccess(w, "Masukkan kode 2FA", challenge)
+		return
+	}
+
+	resp, err := h.issueTokens(r.Context(), user)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat token")
+		return
+	}
+
+	utils.WriteSuccess(w, "Login berhasil", resp)
+}
+
+// findOrCreateOIDCUser mencari user yang terhubung ke identity. Jika belum ada, identity
+// dihubungkan ke user lokal dengan email terverifikasi yang sama, atau user baru dibuat.
+func (h *Handler) findOrCreateOIDCUser(ctx context.Context, id oidc.Identity) (models.User, error) {
+	userID, err := h.Users.FindIdentity(ctx, id.Issuer, id.Subject)
+	if err == nil {
+		return h.Users.Get(ctx, userID)
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return models.User{}, err
+	}
+
+	// Menghubungkan berdasarkan email hanya aman jika kedua sisi sudah memverifikasi email-nya
+	if id.Email == "" || !id.EmailVerified {
+		return models.User{}, errOIDCEmailUnverified
+	}
+
+	user, err := h.Users.GetByEmail(ctx, id.Email)
+	switch {
+	case err == nil && user.EmailVerifiedAt == nil:
+		return models.User{}, errOIDCEmailConflict
+	case errors.Is(err, store.ErrNotFound):
+		if user, err = h.createOIDCUser(ctx, id); err != nil {
+			return models.User{}, err
+		}
+	case err != nil:
+		return models.User{}, err
+	}
+
+	err = h.Users.LinkIdentity(ctx, user.ID, id.Issuer, id.Subject, id.Email)
+	if errors.Is(err, store.ErrDuplicate) {
+		// Identity yang sama baru saja dihubungkan oleh callback lain di saat bersamaan
+		if userID, err = h.Users.FindIdentity(ctx, id.Issuer, id.Subject); err != nil {
+			return models.User{}, err
+		}
+		return h.Users.Get(ctx, userID)
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// createOIDCUser membuat user baru (just-in-time) dari identity. Password diisi acak
+// sehingga user ini hanya bisa login lewat SSO sampai melakukan reset password.
+func (h *Handler) createOIDCUser(ctx context.Context, id oidc.Identity) (models.User, error) {
+	randomPassword, err := oidc.RandomString(32)
+	if err != nil {
+		return models.User{}, err
+	}
+	hashedPassword, err := utils.HashPassword(randomPassword)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	username, err := h.uniqueUsername(ctx, id)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	verifiedAt := time.Now().UTC()
+	user := models.User{
+		Username:        username,
+		Email:           id.Email,
+		PasswordHash:    hashedPassword,
+		FullName:        id.FullName,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	if err := h.Users.Create(ctx, &user); err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// uniqueUsername memakai claim username (atau bagian depan email) dan menambahkan angka jika sudah dipakai
+func (h *Handler) uniqueUsername(ctx context.Context, id oidc.Identity) (string, error) {
+	base := id.Username
+	if base == "" {
+		base = strings.SplitN(id.Email, "@", 2)[0]
+	}
+	base = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+		return -1
+	}, base)
+	if base == "" {
+		base = "user"
+	}
+	// Dipotong per karakter, bukan per byte, supaya huruf multi-byte tidak terbelah
+	if runes := []rune(base); len(runes) > 40 {
+		base = string(runes[:40])
+	}
+
+	username := base
+	for i := 2; ; i++ {
+		taken, err := h.Users.UsernameTaken(ctx, username, 0)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return username, nil
+		}
+		username = base + strconv.Itoa(i)
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"notes-api/internal/models"
+	"notes-api/internal/oidc"
+	"notes-api/internal/oidc/mock"
+	"notes-api/internal/store/memory"
+	"notes-api/internal/utils"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// setupOIDC menjalankan mock identity provider dan mengembalikan Provider yang terhubung ke sana
+func setupOIDC(t *testing.T) *oidc.Provider {
+	t.Helper()
+
+	var provider *mock.Provider
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provider.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	var err error
+	if provider, err = mock.New(srv.URL, "notes-api"); err != nil {
+		t.Fatal(err)
+	}
+
+	return oidc.NewProvider(oidc.Config{
+		Issuer:      srv.URL,
+		ClientID:    "notes-api",
+		RedirectURL: "http://localhost:5173/oidc/callback",
+		Scopes:      []string{"openid", "email", "profile"},
+		Claims:      oidc.ClaimMappingFromEnv(),
+	})
+}
+
+// ssoLogin menjalankan alur SSO lengkap: authorize, login di mock provider, lalu callback
+// dengan cookie state dari authorize
+func ssoLogin(t *testing.T, h http.Handler) *httptest.ResponseRecorder {
+	t.Helper()
+
+	callback, cookies := ssoAuthorize(t, h)
+	req := newRequestAs(t, 0, http.MethodPost, "/api/oidc/callback", callback)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return serve(h, req)
+}
+
+// ssoAuthorize memanggil authorize dan login di mock provider, lalu mengembalikan body
+// callback dan cookie yang diset authorize
+func ssoAuthorize(t *testing.T, h http.Handler) (models.OIDCCallbackRequest, []*http.Cookie) {
+	t.Helper()
+
+	rec := doAs(t, h, 0, http.MethodGet, "/api/oidc/authorize", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("authorize: status %d: %s", rec.Code, rec.Body.String())
+	}
+	var authorize struct {
+		Data struct {
+			AuthorizationURL string `json:"authorization_url"`
+		} `json:"data"`
+	}
+	decode(t, rec, &authorize)
+
+	// Mock provider langsung redirect kembali ke frontend dengan code dan state
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authorize.Data.AuthorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	redirect, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return models.OIDCCallbackRequest{
+		Code:  redirect.Query().Get("code"),
+		State: redirect.Query().Get("state"),
+	}, rec.Result().Cookies()
+}
+
+func TestOIDCCallbackRequiresStateCookie(t *testing.T) {
+	h := New(memory.New())
+	h.OIDC = setupOIDC(t)
+	r := chi.NewRouter()
+	r.Get("/api/oidc/authorize", h.OIDCAuthorize)
+	r.Post("/api/oidc/callback", h.OIDCCallback)
+
+	// Callback dengan state yang dibuat di browser lain (tanpa cookie-nya) ditolak
+	callback, cookies := ssoAuthorize(t, r)
+	if len(cookies) != 1 || cookies[0].Name != oidcStateCookie || !cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteLaxMode {
+		t.Fatalf("authorize harus menyet cookie state HttpOnly SameSite=Lax: %+v", cookies)
+	}
+	expectStatus(t, "callback tanpa cookie", doAs(t, r, 0, http.MethodPost, "/api/oidc/callback", callback), http.StatusBadRequest)
+
+	// Cookie dari login lain juga tidak cocok dengan state ini
+	_, otherCookies := ssoAuthorize(t, r)
+	req := newRequestAs(t, 0, http.MethodPost, "/api/oidc/callback", callback)
+	req.AddCookie(otherCookies[0])
+	expectStatus(t, "callback dengan cookie login lain", serve(r, req), http.StatusBadRequest)
+
+	// State tidak terpakai oleh percobaan yang ditolak, jadi browser asli tetap bisa login
+	req = newRequestAs(t, 0, http.MethodPost, "/api/oidc/callback", callback)
+	req.AddCookie(cookies[0])
+	expectStatus(t, "callback dengan cookie yang benar", serve(r, req), http.StatusOK)
+}
+
+func TestOIDCCallbackRequiresTwoFactor(t *testing.T) {
+	st := memory.New()
+	h := New(st)
+	h.OIDC = setupOIDC(t)
+	r := chi.NewRouter()
+	r.Get("/api/oidc/authorize", h.OIDCAuthorize)
+	r.Post("/api/oidc/callback", h.OIDCCallback)
+
+	// Login pertama membuat user baru dan langsung mendapat token
+	rec := ssoLogin(t, r)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login SSO pertama: status %d: %s", rec.Code, rec.Body.String())
+	}
+	var login struct {
+		Data models.LoginResponse `json:"data"`
+	}
+	decode(t, rec, &login)
+	if login.Data.Token == "" || login.Data.User.Username != "mockuser" {
+		t.Fatalf("response login tidak sesuai: %+v", login.Data)
+	}
+
+	// Setelah 2FA aktif, SSO harus berhenti di challenge seperti login password
+	ctx := context.Background()
+	const secret = "JBSWY3DPEHPK3PXP"
+	if err := st.Users.SetTOTPSecret(ctx, login.Data.User.ID, secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Users.EnableTwoFactor(ctx, login.Data.User.ID, secret, 0, []string{"hash"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = ssoLogin(t, r)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login SSO dengan 2FA: status %d: %s", rec.Code, rec.Body.String())
+	}
+	var challenge struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	decode(t, rec, &challenge)
+	if challenge.Data["two_factor_required"] != true || challenge.Data["challenge_token"] == "" {
+		t.Fatalf("seharusnya challenge 2FA: %s", rec.Body.String())
+	}
+	if _, ok := challenge.Data["token"]; ok {
+		t.Fatal("token tidak boleh diberikan sebelum kode 2FA dikirim")
+	}
+
+	// Challenge tersimpan untuk user ini, siap dipakai di /api/login/2fa
+	token, _ := challenge.Data["challenge_token"].(string)
+	if c, err := st.Tokens.LoginChallenge(ctx, utils.HashToken(token)); err != nil || c.UserID != login.Data.User.ID {
+		t.Fatalf("challenge tidak tersimpan: %v", err)
+	}
+}
+
+func TestUniqueUsernameTruncatesByRune(t *testing.T) {
+	st := memory.New()
+	h := New(st)
+
+	name := strings.Repeat("é", 45)
+	username, err := h.uniqueUsername(context.Background(), oidc.Identity{Username: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utf8.ValidString(username) || utf8.RuneCountInString(username) != 40 {
+		t.Fatalf("username %q seharusnya 40 karakter UTF-8 yang valid", username)
+	}
+
+	// Username yang sudah dipakai diberi angka di belakang potongan yang sama
+	if err := st.Users.Create(context.Background(), &models.User{Username: username, Email: "a@example.com", PasswordHash: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	next, err := h.uniqueUsername(context.Background(), oidc.Identity{Username: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != username+"2" {
+		t.Fatalf("username berikutnya %q, seharusnya %q", next, username+"2")
+	}
+}
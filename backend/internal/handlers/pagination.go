@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"strconv"
+	"time"
+)
+
+// Default dan batas maksimal jumlah catatan per halaman
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// sortFields berisi nilai parameter sort yang didukung
+var sortFields = map[string]bool{
+	store.SortCreatedAt: true,
+	store.SortUpdatedAt: true,
+	store.SortTitle:     true,
+}
+
+// pageParams berisi parameter pagination dari query string
+type pageParams struct {
+	Limit  int
+	Sort   string
+	Order  string
+	Cursor *pageCursor
+}
+
+// pageCursor menyimpan posisi item terakhir dari halaman sebelumnya.
+// Dikirim ke client dalam bentuk base64 supaya tidak bergantung pada format internal.
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+// parsePageParams membaca limit, cursor, sort dan order dari query string
+func parsePageParams(r *http.Request) (pageParams, error) {
+	q := r.URL.Query()
+	p := pageParams{Limit: defaultPageLimit, Sort: store.SortCreatedAt, Order: "desc"}
+
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return p, errors.New("Parameter limit tidak valid")
+		}
+		if n > maxPageLimit {
+			n = maxPageLimit
+		}
+		p.Limit = n
+	}
+
+	if sort := q.Get("sort"); sort != "" {
+		if !sortFields[sort] {
+			return p, errors.New("Parameter sort harus updated_at, created_at, atau title")
+		}
+		p.Sort = sort
+	}
+
+	if order := q.Get("order"); order != "" {
+		if order != "asc" && order != "desc" {
+			return p, errors.New("Parameter order harus asc atau desc")
+		}
+		p.Order = order
+	}
+
+	if cursor := q.Get("cursor"); cursor != "" {
+		c, err := decodeCursor(cursor)
+		if err != nil {
+			return p, errors.New("Parameter cursor tidak valid")
+		}
+		// Cursor hanya berlaku untuk urutan yang sama dengan saat cursor dibuat
+		if c.Sort != p.Sort || c.Order != p.Order {
+			return p, errors.New("Cursor tidak cocok dengan parameter sort/order")
+		}
+		p.Cursor = c
+	}
+
+	return p, nil
+}
+
+// storePage mengubah parameter pagination menjadi store.Page.
+// Satu baris lebih diambil untuk mengetahui apakah masih ada halaman berikutnya.
+func storePage(p pageParams) (store.Page, error) {
+	page := store.Page{Limit: p.Limit + 1, Sort: p.Sort, Desc: p.Order == "desc"}
+
+	if p.Cursor != nil {
+		after := &store.Cursor{ID: p.Cursor.ID}
+		if p.Sort == store.SortTitle {
+			after.Title = p.Cursor.Value
+		} else {
+			t, err := time.Parse(time.RFC3339Nano, p.Cursor.Value)
+			if err != nil {
+				return page, err
+			}
+			after.Time = t
+		}
+		page.After = after
+	}
+
+	return page, nil
+}
+
+// trimPage memotong hasil query sesuai limit dan membuat cursor halaman berikutnya
+func trimPage(notes []models.Note, p pageParams) ([]models.Note, *string) {
+	if len(notes) <= p.Limit {
+		return notes, nil
+	}
+
+	notes = notes[:p.Limit]
+	last := notes[len(notes)-1]
+
+	c := pageCursor{Sort: p.Sort, Order: p.Order, ID: last.ID}
+	switch p.Sort {
+	case store.SortTitle:
+		c.Value = last.Title
+	case store.SortUpdatedAt:
+		c.Value = last.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	default:
+		c.Value = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	next := encodeCursor(c)
+	return notes, &next
+}
+
+// encodeCursor mengubah cursor menjadi string base64 yang aman untuk URL
+func encodeCursor(c pageCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor kebalikan dari encodeCursor
+func decodeCursor(s string) (*pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	if !sortFields[c.Sort] {
+		return nil, errors.New("sort pada cursor tidak dikenal")
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"notes-api/internal/mail"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"strings"
+	"time"
+)
+
+// Masa berlaku link reset password
+const passwordResetTTL = time.Hour
+
+// ForgotPassword mengirim link reset password ke email user.
+// Response selalu sama supaya tidak bisa dipakai untuk mengecek email mana yang terdaftar.
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Email wajib diisi")
+		return
+	}
+
+	// Dikerjakan di background supaya waktu response juga tidak membocorkan apa-apa.
+	// Context request tidak dipakai karena sudah selesai begitu response terkirim.
+	go h.sendPasswordReset(context.Background(), email)
+
+	utils.WriteSuccess(w, "Jika email terdaftar, link reset password sudah dikirim", nil)
+}
+
+// ResetPassword mengganti password memakai token dari email. Token hanya bisa dipakai sekali
+// dan semua sesi lama user dicabut.
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	if req.Token == "" || strings.TrimSpace(req.NewPassword) == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Token dan password baru wajib diisi")
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses password")
+		return
+	}
+
+	// Token ditandai terpakai dan token reset lain milik user yang sama dibuang
+	userID, err := h.Users.ResetPassword(r.Context(), utils.HashToken(req.Token), hashedPassword)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusBadRequest, "Link reset password tidak valid atau sudah kedaluwarsa")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mereset password")
+		return
+	}
+
+	if err := h.Tokens.RevokeAllForUser(r.Context(), userID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mencabut sesi lama")
+		return
+	}
+
+	utils.WriteSuccess(w, "Password berhasil direset, silakan login dengan password baru", nil)
+}
+
+// sendPasswordReset membuat token reset dan mengirim link-nya jika email terdaftar
+func (h *Handler) sendPasswordReset(ctx context.Context, email string) {
+	user, err := h.Users.GetByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Println("Gagal mencari user untuk reset password:", err)
+		}
+		return
+	}
+
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		log.Println("Gagal membuat token reset password:", err)
+		return
+	}
+
+	if err := h.Users.CreatePasswordReset(ctx, user.ID, hash, time.Now().UTC().Add(passwordResetTTL)); err != nil {
+		log.Println("Gagal menyimpan token reset password:", err)
+		return
+	}
+
+	link := frontendLink("/reset-password", url.Values{"token": {token}})
+	err = h.Mailer.Send(mail.Message{
+		To:      email,
+		Subject: "Reset password Notes",
+		Body: "Kami menerima permintaan reset password untuk akun kamu.\n\n" +
+			"Buka link berikut untuk membuat password baru (berlaku 1 jam):\n" + link + "\n\n" +
+			"Abaikan email ini jika kamu tidak meminta reset password.",
+	})
+	if err != nil {
+		log.Println("Gagal mengirim email reset password:", err)
+	}
+}
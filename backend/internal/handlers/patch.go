@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+)
+
+// decodeMergePatch membaca body JSON Merge Patch (RFC 7396) sebagai map field -> nilai mentah.
+// Field yang tidak ada di map berarti tidak diubah, nilai "null" berarti field dikosongkan.
+func decodeMergePatch(r *http.Request) (map[string]json.RawMessage, error) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/merge-patch+json" && mediaType != "application/json") {
+			return nil, errors.New("Content-Type harus application/merge-patch+json")
+		}
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		return nil, errors.New("Body harus berupa object JSON")
+	}
+
+	return patch, nil
+}
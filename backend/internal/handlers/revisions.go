@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"notes-api/internal/middleware"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Batas nilai retention revisi yang boleh diset user
+const (
+	minRevisionRetention = 1
+	maxRevisionRetention = 500
+)
+
+// GetNoteRevisions mengambil riwayat revisi sebuah catatan, terbaru dulu
+func (h *Handler) GetNoteRevisions(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	noteID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	revisions, err := h.Revisions.List(r.Context(), userID, noteID)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Catatan tidak ditemukan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil riwayat revisi")
+		return
+	}
+
+	utils.WriteSuccess(w, "Riwayat revisi berhasil diambil", revisions)
+}
+
+// GetNoteRevisionDiff membandingkan dua revisi dalam format unified diff.
+// Query: from (wajib) dan to (opsional); nilai "current" berarti isi catatan saat ini.
+func (h *Handler) GetNoteRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	noteID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	if from == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Parameter from wajib diisi")
+		return
+	}
+	if to == "" {
+		to = "current"
+	}
+
+	fromText, err := h.loadRevisionText(r.Context(), userID, noteID, from)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Revisi "+from+" tidak ditemukan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	toText, err := h.loadRevisionText(r.Context(), userID, noteID, to)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Revisi "+to+" tidak ditemukan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromText),
+		B:        difflib.SplitLines(toText),
+		FromFile: revisionLabel(from),
+		ToFile:   revisionLabel(to),
+		Context:  3,
+	})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat diff revisi")
+		return
+	}
+
+	utils.WriteSuccess(w, "Diff revisi berhasil dibuat", models.RevisionDiff{
+		From: from,
+		To:   to,
+		Diff: diff,
+	})
+}
+
+// RestoreNoteRevision mengembalikan isi catatan ke revisi tertentu.
+// Isi catatan saat ini disimpan dulu sebagai revisi baru supaya restore juga bisa di-undo.
+func (h *Handler) RestoreNoteRevision(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	noteID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	revision, err := strconv.Atoi(chi.URLParam(r, "rev"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Nomor revisi tidak valid")
+		return
+	}
+
+	rev, err := h.Revisions.Restore(r.Context(), userID, noteID, revision)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Catatan tidak ditemukan")
+		return
+	}
+	if errors.Is(err, store.ErrRevisionNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Revisi tidak ditemukan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal me-restore revisi")
+		return
+	}
+
+	utils.WriteSuccess(w, "Catatan berhasil di-restore ke revisi "+strconv.Itoa(revision), rev)
+}
+
+// GetRevisionSettings mengambil pengaturan retention revisi milik user
+func (h *Handler) GetRevisionSettings(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	retention, err := h.Revisions.Retention(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil pengaturan revisi")
+		return
+	}
+
+	utils.WriteSuccess(w, "Pengaturan revisi berhasil diambil", models.RevisionSettings{Retention: retention})
+}
+
+// UpdateRevisionSettings mengubah retention revisi dan langsung membuang revisi lama yang melebihi batas baru
+func (h *Handler) UpdateRevisionSettings(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var settings models.RevisionSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	if settings.Retention < minRevisionRetention || settings.Retention > maxRevisionRetention {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Retention harus antara %d dan %d", minRevisionRetention, maxRevisionRetention))
+		return
+	}
+
+	if err := h.Revisions.SetRetention(r.Context(), userID, settings.Retention); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengupdate pengaturan revisi")
+		return
+	}
+
+	utils.WriteSuccess(w, "Pengaturan revisi berhasil diupdate", settings)
+}
+
+// loadRevisionText mengambil judul + isi dari sebuah revisi (atau "current") sebagai teks untuk di-diff
+func (h *Handler) loadRevisionText(ctx context.Context, userID, noteID int, revision string) (string, error) {
+	if revision == "current" {
+		note, err := h.Notes.Get(ctx, userID, noteID)
+		if err != nil {
+			return "", err
+		}
+		return note.Title + "\n\n" + note.Content + "\n", nil
+	}
+
+	rev, err := strconv.Atoi(revision)
+	if err != nil {
+		return "", fmt.Errorf("Revisi %q tidak valid", revision)
+	}
+
+	found, err := h.Revisions.Get(ctx, userID, noteID, rev)
+	if err != nil {
+		return "", err
+	}
+	return found.Title + "\n\n" + found.Content + "\n", nil
+}
+
+// revisionLabel membuat nama "file" untuk header unified diff
+func revisionLabel(revision string) string {
+	if revision == "current" {
+		return "current"
+	}
+	return "revisi-" + revision
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"notes-api/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Routes mendaftarkan semua endpoint API ke router beserta middleware auth, verifikasi
+// email dan scope-nya. Middleware global (logger, CORS) dipasang pemanggil.
+func (h *Handler) Routes(r chi.Router) {
+	// Public key untuk memverifikasi JWT (dipakai service lain)
+	r.Get("/.well-known/jwks.json", JWKS)
+
+	// Routes tanpa auth
+	r.Post("/api/register", h.Register)
+	r.Post("/api/login", h.Login)
+	r.Post("/api/login/2fa", h.LoginTwoFactor)
+	r.Get("/api/oidc/authorize", h.OIDCAuthorize)
+	r.Post("/api/oidc/callback", h.OIDCCallback)
+	r.Post("/api/token/refresh", h.RefreshToken)
+	r.Get("/api/email/confirm", h.ConfirmEmailChange)
+	r.Post("/api/password/forgot", h.ForgotPassword)
+	r.Post("/api/password/reset", h.ResetPassword)
+	r.Get("/api/verify-email", h.VerifyEmail)
+	r.Post("/api/verify-email/resend", h.ResendVerification)
+
+	// Routes dengan auth (protected)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Auth(h.Tokens)) // Semua route di grup ini butuh JWT token atau personal access token
+
+		// Route akun hanya bisa diakses lewat login biasa, tidak dengan personal access token
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.SessionOnly)
+
+			// Logout
+			r.Post("/api/logout", h.Logout)
+			r.Post("/api/logout-all", h.LogoutAll)
+
+			// Profil user yang sedang login
+			r.Get("/api/me", h.GetMe)
+			r.Put("/api/me", h.UpdateMe)
+			r.Post("/api/me/password", h.ChangePassword)
+
+			// Two-factor authentication (TOTP)
+			r.Post("/api/me/2fa/setup", h.SetupTwoFactor)
+			r.Post("/api/me/2fa/confirm", h.ConfirmTwoFactor)
+			r.Post("/api/me/2fa/disable", h.DisableTwoFactor)
+			r.Post("/api/me/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+
+			// Personal access token
+			r.Get("/api/tokens", h.GetAccessTokens)
+			r.Post("/api/tokens", h.CreateAccessToken)
+			r.Delete("/api/tokens/{id}", h.RevokeAccessToken)
+		})
+
+		// Route yang mengubah data hanya untuk email terverifikasi (lihat UNVERIFIED_ACCESS)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireVerifiedForWrite(h.Users))
+
+			// Scope yang dibutuhkan jika request memakai personal access token
+			notesRead := middleware.RequireScope(middleware.ScopeNotesRead)
+			notesWrite := middleware.RequireScope(middleware.ScopeNotesWrite)
+			tagsWrite := middleware.RequireScope(middleware.ScopeTagsWrite)
+
+			// Folders
+			r.With(notesRead).Get("/api/folders", h.GetFolders)
+			r.With(notesRead).Get("/api/folders/tree", h.GetFolderTree)
+			r.With(notesWrite).Post("/api/folders", h.CreateFolder)
+			r.With(notesWrite).Put("/api/folders/{id}", h.UpdateFolder)
+			r.With(notesWrite).Patch("/api/folders/{id}", h.PatchFolder)
+			r.With(notesWrite).Post("/api/folders/{id}/move", h.MoveFolder)
+			r.With(notesWrite).Delete("/api/folders/{id}", h.DeleteFolder)
+
+			// Notes
+			r.With(notesRead).Get("/api/notes", h.GetNotes)
+			r.With(notesRead).Get("/api/notes/{id}", h.GetNoteByID)
+			r.With(notesRead).Get("/api/folders/{id}/notes", h.GetNotesByFolder)
+			r.With(notesRead).Get("/api/tags/{id}/notes", h.GetNotesByTag)
+			r.With(notesWrite).Post("/api/notes", h.CreateNote)
+			r.With(notesWrite).Post("/api/notes/bulk", h.BulkNotes)
+			r.With(notesWrite).Put("/api/notes/{id}", h.UpdateNote)
+			r.With(notesWrite).Patch("/api/notes/{id}", h.PatchNote)
+			r.With(notesWrite).Delete("/api/notes/{id}", h.DeleteNote)
+
+			// Revisi catatan
+			r.With(notesRead).Get("/api/notes/{id}/revisions", h.GetNoteRevisions)
+			r.With(notesRead).Get("/api/notes/{id}/revisions/diff", h.GetNoteRevisionDiff)
+			r.With(notesWrite).Post("/api/notes/{id}/revisions/{rev}/restore", h.RestoreNoteRevision)
+			r.With(notesRead).Get("/api/settings/revisions", h.GetRevisionSettings)
+			r.With(notesWrite).Put("/api/settings/revisions", h.UpdateRevisionSettings)
+
+			// Trash
+			r.With(notesRead).Get("/api/trash", h.GetTrash)
+			r.With(notesWrite).Delete("/api/trash", h.EmptyTrash)
+			r.With(notesWrite).Post("/api/trash/{type}/{id}/restore", h.RestoreTrashItem)
+			r.With(notesWrite).Delete("/api/trash/{type}/{id}", h.DeleteTrashItem)
+
+			// Search
+			r.With(notesRead).Get("/api/search", h.Search)
+
+			// Tags
+			r.With(notesRead).Get("/api/tags", h.GetTags)
+			r.With(tagsWrite).Post("/api/tags", h.CreateTag)
+			r.With(tagsWrite).Put("/api/tags/{id}", h.UpdateTag)
+			r.With(tagsWrite).Post("/api/tags/{id}/merge", h.MergeTag)
+			r.With(tagsWrite).Delete("/api/tags/{id}", h.DeleteTag)
+
+			// Tag assignment
+			r.With(tagsWrite).Post("/api/notes/{noteId}/tags/{tagId}", h.AssignTagToNote)
+			r.With(tagsWrite).Delete("/api/notes/{noteId}/tags/{tagId}", h.RemoveTagFromNote)
+		})
+	})
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"html"
+	"net/http"
+	"notes-api/internal/middleware"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Default dan batas maksimal jumlah hasil pencarian per request
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// Panjang potongan isi catatan yang dikembalikan sebagai snippet
+const (
+	snippetBefore = 60
+	snippetAfter  = 140
+)
+
+// Kata yang lebih pendek dari ini tidak masuk FULLTEXT index (default innodb_ft_min_token_size)
+const minSearchTokenLen = 3
+
+// Search mencari catatan dengan FULLTEXT index, diurutkan berdasarkan relevansi.
+// Mendukung "frasa", awalan*, dan -pengecualian, serta filter folder_id, tag_id dan favorite.
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	q := r.URL.Query()
+
+	// MySQL tidak bisa mencari hanya dengan pengecualian, jadi minimal harus ada satu term positif
+	terms := parseSearchQuery(q.Get("q"))
+	if !store.HasPositiveTerm(terms) {
+		utils.WriteError(w, http.StatusBadRequest, "Parameter q wajib berisi minimal satu kata yang dicari")
+		return
+	}
+
+	query := store.SearchQuery{Terms: terms, Limit: defaultSearchLimit, Favorite: q.Get("favorite") == "true"}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			utils.WriteError(w, http.StatusBadRequest, "Parameter limit tidak valid")
+			return
+		}
+		if n > maxSearchLimit {
+			n = maxSearchLimit
+		}
+		query.Limit = n
+	}
+
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			utils.WriteError(w, http.StatusBadRequest, "Parameter offset tidak valid")
+			return
+		}
+		query.Offset = n
+	}
+
+	if v := q.Get("folder_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Parameter folder_id tidak valid")
+			return
+		}
+		query.FolderID = n
+	}
+
+	if v := q.Get("tag_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Parameter tag_id tidak valid")
+			return
+		}
+		query.TagID = n
+	}
+
+	results, err := h.Notes.Search(r.Context(), userID, query)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mencari catatan")
+		return
+	}
+
+	highlighter := buildHighlighter(terms)
+	for i := range results {
+		results[i].TitleHighlight = highlight(results[i].Title, highlighter)
+		results[i].Snippet = makeSnippet(results[i].Content, highlighter)
+	}
+
+	utils.WriteSuccess(w, "Hasil pencarian berhasil diambil", results)
+}
+
+// parseSearchQuery memecah query user menjadi daftar SearchTerm.
+// Karakter operator MySQL lain dibuang supaya user tidak bisa merusak query boolean.
+func parseSearchQuery(q string) []store.SearchTerm {
+	var terms []store.SearchTerm
+	runes := []rune(q)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		exclude := false
+		if runes[i] == '-' {
+			exclude = true
+			i++
+			if i >= len(runes) {
+				break
+			}
+		}
+
+		// Frasa di dalam tanda kutip
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			words := splitWords(string(runes[i+1 : end]))
+			if len(words) > 0 {
+				terms = append(terms, store.SearchTerm{Text: strings.Join(words, " "), Phrase: true, Exclude: exclude})
+			}
+			i = end + 1
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+		raw := string(runes[i:end])
+		i = end
+
+		prefix := strings.HasSuffix(raw, "*")
+		words := splitWords(raw)
+		for j, word := range words {
+			if utf8.RuneCountInString(word) < minSearchTokenLen {
+				continue
+			}
+			terms = append(terms, store.SearchTerm{
+				Text:    word,
+				Exclude: exclude,
+				Prefix:  prefix && j == len(words)-1,
+			})
+		}
+	}
+
+	return terms
+}
+
+// splitWords memecah teks menjadi kata yang hanya berisi huruf, angka dan underscore
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return !isWordRune(r) })
+}
+
+// isWordRune mengecek apakah r bagian dari kata (huruf, angka atau underscore, termasuk non-ASCII)
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// Karakter kata dan pemisah kata untuk pola regexp, sama dengan isWordRune
+const (
+	wordClass    = `[\p{L}\p{Nd}_]`
+	nonWordClass = `[^\p{L}\p{Nd}_]`
+)
+
+// highlighter mencari bagian teks yang cocok dengan term positif, satu regexp per term.
+// Batas kata dicek dengan isWordRune karena \b di regexp Go hanya mengenal huruf ASCII,
+// sehingga kata seperti "café" atau "über" tidak pernah cocok.
+type highlighter []*regexp.Regexp
+
+// buildHighlighter membuat highlighter untuk semua term positif
+func buildHighlighter(terms []store.SearchTerm) highlighter {
+	var h highlighter
+	for _, t := range terms {
+		if t.Exclude {
+			continue
+		}
+
+		var pattern string
+		switch {
+		case t.Phrase:
+			words := strings.Fields(t.Text)
+			for i, word := range words {
+				words[i] = regexp.QuoteMeta(word)
+			}
+			pattern = strings.Join(words, nonWordClass+`+`)
+		case t.Prefix:
+			pattern = regexp.QuoteMeta(t.Text) + wordClass + `*`
+		default:
+			pattern = regexp.QuoteMeta(t.Text)
+		}
+		h = append(h, regexp.MustCompile(`(?i)`+pattern))
+	}
+	return h
+}
+
+// find mengembalikan posisi [awal, akhir) semua kecocokan yang berada di batas kata,
+// urut dan tidak saling tumpang tindih
+func (h highlighter) find(text string) [][2]int {
+	var matches [][2]int
+	for _, re := range h {
+		for pos := 0; pos < len(text); {
+			loc := re.FindStringIndex(text[pos:])
+			if loc == nil {
+				break
+			}
+			start, end := pos+loc[0], pos+loc[1]
+			if atWordBoundary(text, start) && atWordBoundary(text, end) {
+				matches = append(matches, [2]int{start, end})
+				pos = end
+				continue
+			}
+			// Bukan kata utuh, coba lagi mulai dari karakter berikutnya
+			_, size := utf8.DecodeRuneInString(text[start:])
+			pos = start + size
+		}
+	}
+
+	// Kecocokan dari term berbeda bisa tumpang tindih, yang mulai lebih awal (lalu yang
+	// lebih panjang) dipakai
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i][0] != matches[j][0] {
+			return matches[i][0] < matches[j][0]
+		}
+		return matches[i][1] > matches[j][1]
+	})
+	result := matches[:0]
+	for _, m := range matches {
+		if len(result) == 0 || m[0] >= result[len(result)-1][1] {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// atWordBoundary mengecek apakah posisi i di text tidak berada di tengah kata
+func atWordBoundary(text string, i int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i:])
+	return i == 0 || i == len(text) || !isWordRune(before) || !isWordRune(after)
+}
+
+// highlight meng-escape teks sebagai HTML dan membungkus bagian yang cocok dengan <mark>
+func highlight(text string, h highlighter) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range h.find(text) {
+		b.WriteString(html.EscapeString(text[last:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		last = m[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+
+	return b.String()
+}
+
+// makeSnippet mengambil potongan isi catatan di sekitar kecocokan pertama
+func makeSnippet(content string, h highlighter) string {
+	start, end := 0, len(content)
+
+	matchAt := 0
+	if matches := h.find(content); len(matches) > 0 {
+		matchAt = matches[0][0]
+	}
+
+	if matchAt > snippetBefore {
+		start = matchAt - snippetBefore
+	}
+	if start+snippetBefore+snippetAfter < end {
+		end = start + snippetBefore + snippetAfter
+	}
+
+	// Jangan memotong di tengah karakter multi-byte
+	for start > 0 && !utf8.RuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !utf8.RuneStart(content[end]) {
+		end++
+	}
+
+	snippet := highlight(content[start:end], h)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(content) {
+		snippet += "…"
+	}
+
+	return snippet
+}
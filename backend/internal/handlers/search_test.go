@@ -0,0 +1,43 @@
+package handlers
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name, query, text, want string
+	}{
+		{"kata ASCII", "rapat", "Rapat tim, bukan rapatkan", "<mark>Rapat</mark> tim, bukan rapatkan"},
+		{"kata non-ASCII", "café", "Kopi di café itu, bukan cafés", "Kopi di <mark>café</mark> itu, bukan cafés"},
+		{"diawali huruf non-ASCII", "über", "über alles, überall", "<mark>über</mark> alles, überall"},
+		{"tidak cocok di tengah kata non-ASCII", "tel", "hôtel tel", "hôtel <mark>tel</mark>"},
+		{"awalan sampai akhir kata non-ASCII", "naï*", "naïveté naïf", "<mark>naïveté</mark> <mark>naïf</mark>"},
+		{"frasa", `"menara eiffel"`, "Menara  Eiffel, Paris", "<mark>Menara  Eiffel</mark>, Paris"},
+		{"kecocokan berdampingan", "foo", "foo foo", "<mark>foo</mark> <mark>foo</mark>"},
+		{"term tumpang tindih", "kopi* kopiah", "kopiah", "<mark>kopiah</mark>"},
+		{"pengecualian tidak disorot", "kopi -teh", "kopi teh", "<mark>kopi</mark> teh"},
+		{"HTML di-escape", "tag", "<b>tag</b>", "&lt;b&gt;<mark>tag</mark>&lt;/b&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlight(tt.text, buildHighlighter(parseSearchQuery(tt.query)))
+			if got != tt.want {
+				t.Errorf("highlight(%q, %q) = %q, seharusnya %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeSnippetStartsNearUnicodeMatch(t *testing.T) {
+	content := ""
+	for i := 0; i < 30; i++ {
+		content += "lorem "
+	}
+	content += "résumé akhir"
+
+	got := makeSnippet(content, buildHighlighter(parseSearchQuery("résumé")))
+	want := "…lorem lorem lorem lorem lorem lorem lorem lorem lorem lorem <mark>résumé</mark> akhir"
+	if got != want {
+		t.Errorf("makeSnippet = %q, seharusnya %q", got, want)
+	}
+}
@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"notes-api/internal/database"
 	"notes-api/internal/middleware"
 	"notes-api/internal/models"
+	"notes-api/internal/store"
 	"notes-api/internal/utils"
 	"strconv"
 	"strings"
@@ -14,31 +15,20 @@ import (
 )
 
 // GetTags mengambil semua tag milik user
-func GetTags(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 
-	query := "SELECT t.id, t.user_id, t.name, t.created_at, COUNT(nt.note_id) as note_count FROM tags t LEFT JOIN note_tags nt ON t.id = nt.tag_id AND nt.note_id IN (SELECT id FROM notes WHERE user_id = ?) WHERE t.user_id = ? GROUP BY t.id ORDER BY t.name ASC"
-	rows, err := database.DB.Query(query, userID, userID)
+	tags, err := h.Tags.List(r.Context(), userID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data tag")
 		return
 	}
-	defer rows.Close()
-
-	tags := []models.Tag{}
-	for rows.Next() {
-		var tag models.Tag
-		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt, &tag.NoteCount); err != nil {
-			continue
-		}
-		tags = append(tags, tag)
-	}
 
 	utils.WriteSuccess(w, "Data tag berhasil diambil", tags)
 }
 
 // CreateTag membuat tag baru
-func CreateTag(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CreateTag(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 
 	var tag models.Tag
@@ -52,76 +42,129 @@ func CreateTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := "INSERT INTO tags (user_id, name) VALUES (?, ?)"
-	result, err := database.DB.Exec(query, userID, tag.Name)
+	tag.UserID = userID
+	err := h.Tags.Create(r.Context(), &tag)
+	if errors.Is(err, store.ErrDuplicate) {
+		utils.WriteError(w, http.StatusConflict, "Tag sudah ada")
+		return
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			utils.WriteError(w, http.StatusConflict, "Tag sudah ada")
-			return
-		}
 		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat tag")
 		return
 	}
 
-	tagID, _ := result.LastInsertId()
-	tag.ID = int(tagID)
-	tag.UserID = userID
-
 	utils.WriteSuccess(w, "Tag berhasil dibuat", tag)
 }
 
-// DeleteTag menghapus tag
-func DeleteTag(w http.ResponseWriter, r *http.Request) {
+// UpdateTag mengganti nama tag
+func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	tagID, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
-	query := "DELETE FROM tags WHERE id = ? AND user_id = ?"
-	result, err := database.DB.Exec(query, tagID, userID)
+	var tag models.Tag
+	if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	if strings.TrimSpace(tag.Name) == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Nama tag wajib diisi")
+		return
+	}
+
+	err := h.Tags.Rename(r.Context(), userID, tagID, tag.Name)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Tag tidak ditemukan")
+		return
+	}
+	if errors.Is(err, store.ErrDuplicate) {
+		utils.WriteError(w, http.StatusConflict, "Tag dengan nama tersebut sudah ada")
+		return
+	}
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal menghapus tag")
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengupdate tag")
+		return
+	}
+
+	tag.ID = tagID
+	tag.UserID = userID
+
+	utils.WriteSuccess(w, "Tag berhasil diupdate", tag)
+}
+
+// MergeTag memindahkan semua relasi note_tags dari tag sumber (URL param id)
+// ke tag tujuan, lalu menghapus tag sumber. Semua dilakukan dalam satu transaksi.
+func (h *Handler) MergeTag(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	sourceID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	var req models.MergeTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	if req.TargetID == sourceID {
+		utils.WriteError(w, http.StatusBadRequest, "Tag tujuan harus berbeda dengan tag sumber")
+		return
+	}
+
+	moved, err := h.Tags.Merge(r.Context(), userID, sourceID, req.TargetID)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Tag sumber tidak ditemukan")
+		return
+	}
+	if errors.Is(err, store.ErrTagNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Tag tujuan tidak ditemukan")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal menggabungkan tag")
+		return
+	}
+
+	utils.WriteSuccess(w, "Tag berhasil digabungkan", map[string]interface{}{
+		"target_id":   req.TargetID,
+		"moved_notes": moved,
+	})
+}
+
+// DeleteTag menghapus tag
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	tagID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	err := h.Tags.Delete(r.Context(), userID, tagID)
+	if errors.Is(err, store.ErrNotFound) {
 		utils.WriteError(w, http.StatusNotFound, "Tag tidak ditemukan")
 		return
 	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal menghapus tag")
+		return
+	}
 
 	utils.WriteSuccess(w, "Tag berhasil dihapus", nil)
 }
 
 // AssignTagToNote menambahkan tag ke catatan
-func AssignTagToNote(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) AssignTagToNote(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	noteID, _ := strconv.Atoi(chi.URLParam(r, "noteId"))
 	tagID, _ := strconv.Atoi(chi.URLParam(r, "tagId"))
 
-	// Cek apakah note milik user
-	var count int
-	checkNote := "SELECT COUNT(*) FROM notes WHERE id = ? AND user_id = ?"
-	database.DB.QueryRow(checkNote, noteID, userID).Scan(&count)
-	if count == 0 {
+	err := h.Tags.Attach(r.Context(), userID, noteID, tagID)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
 		utils.WriteError(w, http.StatusNotFound, "Catatan tidak ditemukan")
 		return
-	}
-
-	// Cek apakah tag milik user
-	checkTag := "SELECT COUNT(*) FROM tags WHERE id = ? AND user_id = ?"
-	database.DB.QueryRow(checkTag, tagID, userID).Scan(&count)
-	if count == 0 {
+	case errors.Is(err, store.ErrTagNotFound):
 		utils.WriteError(w, http.StatusNotFound, "Tag tidak ditemukan")
 		return
-	}
-
-	// Insert relasi
-	query := "INSERT INTO note_tags (note_id, tag_id) VALUES (?, ?)"
-	_, err := database.DB.Exec(query, noteID, tagID)
-	if err != nil {
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			utils.WriteError(w, http.StatusConflict, "Tag sudah ditambahkan ke catatan ini")
-			return
-		}
+	case errors.Is(err, store.ErrDuplicate):
+		utils.WriteError(w, http.StatusConflict, "Tag sudah ditambahkan ke catatan ini")
+		return
+	case err != nil:
 		utils.WriteError(w, http.StatusInternalServerError, "Gagal menambahkan tag ke catatan")
 		return
 	}
@@ -130,32 +173,22 @@ func AssignTagToNote(w http.ResponseWriter, r *http.Request) {
 }
 
 // RemoveTagFromNote menghapus tag dari catatan
-func RemoveTagFromNote(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) RemoveTagFromNote(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r)
 	noteID, _ := strconv.Atoi(chi.URLParam(r, "noteId"))
 	tagID, _ := strconv.Atoi(chi.URLParam(r, "tagId"))
 
-	// Cek apakah note milik user
-	var count int
-	checkNote := "SELECT COUNT(*) FROM notes WHERE id = ? AND user_id = ?"
-	database.DB.QueryRow(checkNote, noteID, userID).Scan(&count)
-	if count == 0 {
+	err := h.Tags.Detach(r.Context(), userID, noteID, tagID)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
 		utils.WriteError(w, http.StatusNotFound, "Catatan tidak ditemukan")
 		return
-	}
-
-	// Hapus relasi
-	query := "DELETE FROM note_tags WHERE note_id = ? AND tag_id = ?"
-	result, err := database.DB.Exec(query, noteID, tagID)
-	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Gagal menghapus tag dari catatan")
-		return
-	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	case errors.Is(err, store.ErrTagNotFound):
 		utils.WriteError(w, http.StatusNotFound, "Tag tidak ditemukan di catatan ini")
 		return
+	case err != nil:
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal menghapus tag dari catatan")
+		return
 	}
 
 	utils.WriteSuccess(w, "Tag berhasil dihapus dari catatan", nil)
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"notes-api/internal/middleware"
+	"notes-api/internal/models"
+	"notes-api/internal/revocation"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"strings"
+	"time"
+)
+
+// RefreshToken menukar refresh token dengan access token baru. Refresh token lama
+// langsung di-rotate; jika token yang sudah di-rotate dipakai lagi, seluruh family dicabut.
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	if strings.TrimSpace(req.RefreshToken) == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Refresh token wajib diisi")
+		return
+	}
+
+	// Token pengganti dibuat dulu supaya store bisa menyimpannya dalam transaksi yang sama
+	// dengan rotasi token lama
+	refreshToken, successor, err := newRefreshToken()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat token")
+		return
+	}
+
+	// Token ditandai sudah dipakai; yang dikembalikan adalah kondisinya sebelum dipakai
+	token, err := h.Tokens.RotateRefreshToken(r.Context(), utils.HashToken(req.RefreshToken), successor)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusUnauthorized, "Refresh token tidak valid")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses refresh token")
+		return
+	}
+
+	if token.RevokedAt != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "Refresh token sudah dicabut")
+		return
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		utils.WriteError(w, http.StatusUnauthorized, "Refresh token sudah kedaluwarsa")
+		return
+	}
+
+	// Token lama dipakai lagi: kemungkinan dicuri, store sudah mencabut semua token di family ini
+	if token.RotatedAt != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "Refresh token sudah pernah dipakai, sesi dicabut")
+		return
+	}
+
+	user, err := h.Users.Get(r.Context(), token.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "Refresh token tidak valid")
+		return
+	}
+	if user.DisabledAt != nil {
+		utils.WriteError(w, http.StatusForbidden, accountDisabledMessage)
+		return
+	}
+
+	resp, err := loginResponse(user, refreshToken)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat token")
+		return
+	}
+
+	utils.WriteSuccess(w, "Token berhasil diperbarui", resp)
+}
+
+// Logout mencabut access token yang sedang dipakai. Jika body berisi refresh_token,
+// seluruh family refresh token tersebut ikut dicabut.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	// Body opsional
+	var req models.RefreshRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	if err := revocation.Revoke(r.Context(), h.Tokens, middleware.GetClaims(r)); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal logout")
+		return
+	}
+
+	if req.RefreshToken != "" {
+		if err := h.Tokens.RevokeRefreshFamily(r.Context(), userID, utils.HashToken(req.RefreshToken)); err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, "Gagal mencabut refresh token")
+			return
+		}
+	}
+
+	utils.WriteSuccess(w, "Logout berhasil", nil)
+}
+
+// LogoutAll mencabut semua access token dan refresh token milik user di semua perangkat
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	if err := h.Tokens.RevokeAllForUser(r.Context(), userID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal logout dari semua perangkat")
+		return
+	}
+
+	// Token yang di-issue di detik yang sama dengan logout-all lolos dari tokens_valid_after,
+	// jadi token yang sedang dipakai dicabut lewat jti-nya
+	if err := revocation.Revoke(r.Context(), h.Tokens, middleware.GetClaims(r)); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal logout dari semua perangkat")
+		return
+	}
+
+	utils.WriteSuccess(w, "Logout dari semua perangkat berhasil", nil)
+}
+
+// issueTokens membuat access token dan refresh token baru untuk sesi login baru
+func (h *Handler) issueTokens(ctx context.Context, user models.User) (models.LoginResponse, error) {
+	familyID, err := utils.RandomID()
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	refreshToken, token, err := newRefreshToken()
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+	token.UserID = user.ID
+	token.FamilyID = familyID
+	if err := h.Tokens.CreateRefreshToken(ctx, token); err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return loginResponse(user, refreshToken)
+}
+
+// newRefreshToken membuat refresh token acak; yang disimpan ke database hanya hash-nya.
+// UserID dan FamilyID diisi pemanggil (atau store saat rotasi).
+func newRefreshToken() (string, store.RefreshToken, error) {
+	refreshToken, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", store.RefreshToken{}, err
+	}
+	return refreshToken, store.RefreshToken{
+		TokenHash: hash,
+		ExpiresAt: time.Now().UTC().Add(utils.RefreshTokenTTL()),
+	}, nil
+}
+
+// loginResponse membuat access token baru lalu menyusun response login beserta refresh token-nya
+func loginResponse(user models.User, refreshToken string) (models.LoginResponse, error) {
+	accessToken, err := utils.GenerateToken(user.ID, user.Email)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return models.LoginResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL().Seconds()),
+		User:         user,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"testing"
+)
+
+// refresh menukar refresh token lewat handler dan mengembalikan status serta response-nya
+func refresh(t *testing.T, h http.Handler, refreshToken string) (int, models.LoginResponse) {
+	t.Helper()
+	rec := doAs(t, h, 0, http.MethodPost, "/api/token/refresh", models.RefreshRequest{RefreshToken: refreshToken})
+
+	var resp struct {
+		Data models.LoginResponse `json:"data"`
+	}
+	decode(t, rec, &resp)
+	return rec.Code, resp.Data
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	forEachStore(t, func(t *testing.T, api http.Handler, st *store.Store) {
+		user, err := st.Users.Get(context.Background(), seedUser(t, st, "budi"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		login, err := New(st).issueTokens(context.Background(), user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		code, rotated := refresh(t, api, login.RefreshToken)
+		if code != http.StatusOK {
+			t.Fatalf("refresh pertama: status %d, seharusnya 200", code)
+		}
+		if rotated.RefreshToken == "" || rotated.RefreshToken == login.RefreshToken {
+			t.Fatal("refresh harus mengembalikan refresh token baru")
+		}
+
+		// Token pengganti langsung bisa dipakai
+		code, latest := refresh(t, api, rotated.RefreshToken)
+		if code != http.StatusOK {
+			t.Fatalf("refresh dengan token pengganti: status %d, seharusnya 200", code)
+		}
+
+		// Token lama dipakai lagi: seluruh family dicabut, termasuk token terbaru
+		if code, _ := refresh(t, api, login.RefreshToken); code != http.StatusUnauthorized {
+			t.Fatalf("refresh dengan token lama: status %d, seharusnya 401", code)
+		}
+		if code, _ := refresh(t, api, latest.RefreshToken); code != http.StatusUnauthorized {
+			t.Fatalf("refresh setelah family dicabut: status %d, seharusnya 401", code)
+		}
+	})
+}
+
+func TestLogoutAll(t *testing.T) {
+	forEachStore(t, func(t *testing.T, api http.Handler, st *store.Store) {
+		userID := seedUser(t, st, "budi")
+		current := accessToken(t, st, userID)
+
+		expectStatus(t, "logout-all", doWithToken(t, api, current, http.MethodPost, "/api/logout-all", nil), http.StatusOK)
+
+		// Token yang dipakai untuk logout-all dicabut lewat jti meskipun di-issue di detik yang sama
+		expectStatus(t, "token yang dipakai logout-all", doWithToken(t, api, current, http.MethodGet, "/api/notes", nil), http.StatusUnauthorized)
+
+		// Token baru sesudah logout-all tetap valid walaupun iat-nya sama dengan tokens_valid_after
+		expectStatus(t, "token baru", doWithToken(t, api, accessToken(t, st, userID), http.MethodGet, "/api/notes", nil), http.StatusOK)
+	})
+}
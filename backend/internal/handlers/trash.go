@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"notes-api/internal/middleware"
+	"notes-api/internal/store"
+	"notes-api/internal/trash"
+	"notes-api/internal/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetTrash mengambil semua catatan dan folder milik user yang ada di trash
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	notes, folders, err := h.Trash.List(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data trash")
+		return
+	}
+
+	utils.WriteSuccess(w, "Data trash berhasil diambil", map[string]interface{}{
+		"notes":          notes,
+		"folders":        folders,
+		"retention_days": trash.RetentionDays(),
+	})
+}
+
+// RestoreTrashItem mengembalikan catatan atau folder dari trash.
+// URL param type: "notes" atau "folders".
+func (h *Handler) RestoreTrashItem(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	itemType := chi.URLParam(r, "type")
+	itemID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	switch itemType {
+	case "notes":
+		h.restoreNote(w, r, userID, itemID)
+	case "folders":
+		h.restoreFolder(w, r, userID, itemID)
+	default:
+		utils.WriteError(w, http.StatusBadRequest, "Tipe item trash harus notes atau folders")
+	}
+}
+
+// DeleteTrashItem menghapus permanen satu catatan atau folder yang ada di trash
+func (h *Handler) DeleteTrashItem(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	itemType := chi.URLParam(r, "type")
+	itemID, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	var err error
+	var notFound string
+	switch itemType {
+	case "notes":
+		err = h.Trash.DeleteNote(r.Context(), userID, itemID)
+		notFound = "Catatan tidak ditemukan di trash"
+	case "folders":
+		err = h.Trash.DeleteFolder(r.Context(), userID, itemID)
+		notFound = "Folder tidak ditemukan di trash"
+	default:
+		utils.WriteError(w, http.StatusBadRequest, "Tipe item trash harus notes atau folders")
+		return
+	}
+
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, notFound)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal menghapus item trash")
+		return
+	}
+
+	utils.WriteSuccess(w, "Item berhasil dihapus permanen", nil)
+}
+
+// EmptyTrash menghapus permanen semua isi trash milik user
+func (h *Handler) EmptyTrash(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	deleted, err := h.Trash.Empty(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengosongkan trash")
+		return
+	}
+
+	utils.WriteSuccess(w, "Trash berhasil dikosongkan", map[string]interface{}{
+		"deleted": deleted,
+	})
+}
+
+// restoreNote mengembalikan catatan dari trash. Jika folder-nya masih di trash,
+// catatan dipindah ke luar folder supaya tetap terlihat.
+func (h *Handler) restoreNote(w http.ResponseWriter, r *http.Request, userID, noteID int) {
+	err := h.Trash.RestoreNote(r.Context(), userID, noteID)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Catatan tidak ditemukan di trash")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal me-restore catatan")
+		return
+	}
+
+	utils.WriteSuccess(w, "Catatan berhasil di-restore", nil)
+}
+
+// restoreFolder mengembalikan folder dari trash beserta sub-folder dan catatan yang ikut
+// terhapus bersamanya. Jika parent-nya masih di trash, folder dipindah ke root.
+func (h *Handler) restoreFolder(w http.ResponseWriter, r *http.Request, userID, folderID int) {
+	restoredFolders, restoredNotes, err := h.Trash.RestoreFolder(r.Context(), userID, folderID)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "Folder tidak ditemukan di trash")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal me-restore folder")
+		return
+	}
+
+	utils.WriteSuccess(w, "Folder berhasil di-restore", map[string]interface{}{
+		"restored_folders": restoredFolders,
+		"restored_notes":   restoredNotes,
+	})
+}
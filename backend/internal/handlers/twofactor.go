@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"notes-api/internal/middleware"
+	"notes-api/internal/models"
+	"notes-api/internal/ratelimit"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"os"
+	"strings"
+	"time"
+)
+
+// Konfigurasi login dua tahap
+const (
+	loginChallengeTTL         = 5 * time.Minute
+	maxLoginChallengeAttempts = 5
+	recoveryCodeCount         = 10
+)
+
+// SetupTwoFactor membuat secret TOTP baru untuk user. 2FA belum aktif sampai
+// kode pertama dikonfirmasi lewat ConfirmTwoFactor.
+func (h *Handler) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	user, err := h.Users.Get(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data user")
+		return
+	}
+	if user.TwoFactorEnabled {
+		utils.WriteError(w, http.StatusConflict, "2FA sudah aktif, matikan dulu untuk membuat secret baru")
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat secret 2FA")
+		return
+	}
+
+	if err := h.Users.SetTOTPSecret(r.Context(), userID, secret); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal menyimpan secret 2FA")
+		return
+	}
+
+	utils.WriteSuccess(w, "Scan QR code lalu konfirmasi dengan kode dari aplikasi authenticator", models.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(totpIssuer(), user.Email, secret),
+	})
+}
+
+// ConfirmTwoFactor mengaktifkan 2FA setelah user membuktikan aplikasi authenticator-nya
+// sudah menghasilkan kode yang benar, lalu mengembalikan recovery code (sekali tampil).
+func (h *Handler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	tf, err := h.Users.TwoFactor(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data user")
+		return
+	}
+	if tf.Enabled {
+		utils.WriteError(w, http.StatusConflict, "2FA sudah aktif")
+		return
+	}
+	if tf.Secret == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Jalankan setup 2FA dulu")
+		return
+	}
+
+	step, ok := utils.ValidateTOTP(tf.Secret, strings.TrimSpace(req.Code), time.Now())
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "Kode 2FA salah")
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat recovery code")
+		return
+	}
+
+	// Gagal jika 2FA diaktifkan request lain atau secret diganti sejak dibaca di atas
+	err = h.Users.EnableTwoFactor(r.Context(), userID, tf.Secret, step, hashes)
+	if errors.Is(err, store.ErrConflict) {
+		utils.WriteError(w, http.StatusConflict, "2FA sudah aktif")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengaktifkan 2FA")
+		return
+	}
+
+	utils.WriteSuccess(w, "2FA berhasil diaktifkan, simpan recovery code di tempat aman", models.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTwoFactor mematikan 2FA. Butuh password dan kode 2FA (atau recovery code).
+func (h *Handler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.TwoFactorDisableRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	user, err := h.Users.Get(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data user")
+		return
+	}
+
+	if !utils.CheckPassword(req.Password, user.PasswordHash) {
+		utils.WriteError(w, http.StatusUnauthorized, "Password salah")
+		return
+	}
+
+	ok, err := h.verifySecondFactor(r.Context(), userID, req.Code)
+	if err == errTwoFactorNotEnabled {
+		utils.WriteError(w, http.StatusBadRequest, "2FA belum aktif")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memverifikasi kode 2FA")
+		return
+	}
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Kode 2FA salah")
+		return
+	}
+
+	if err := h.Users.DisableTwoFactor(r.Context(), userID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mematikan 2FA")
+		return
+	}
+
+	utils.WriteSuccess(w, "2FA berhasil dimatikan", nil)
+}
+
+// RegenerateRecoveryCodes mengganti semua recovery code lama dengan yang baru. Butuh kode 2FA.
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	ok, err := h.verifySecondFactor(r.Context(), userID, req.Code)
+	if err == errTwoFactorNotEnabled {
+		utils.WriteError(w, http.StatusBadRequest, "2FA belum aktif")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memverifikasi kode 2FA")
+		return
+	}
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "Kode 2FA salah")
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat recovery code")
+		return
+	}
+
+	if err := h.Users.ReplaceRecoveryCodes(r.Context(), userID, hashes); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat recovery code")
+		return
+	}
+
+	utils.WriteSuccess(w, "Recovery code baru berhasil dibuat, recovery code lama tidak berlaku lagi", models.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// LoginTwoFactor adalah tahap kedua login: menukar challenge token dari Login
+// dan kode 2FA (atau recovery code) dengan access token dan refresh token.
+func (h *Handler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	if req.ChallengeToken == "" || strings.TrimSpace(req.Code) == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Challenge token dan kode 2FA wajib diisi")
+		return
+	}
+
+	challenge, err := h.Tokens.LoginChallenge(r.Context(), utils.HashToken(req.ChallengeToken))
+	if errors.Is(err, store.ErrNotFound) || (err == nil && time.Now().After(challenge.ExpiresAt)) {
+		utils.WriteError(w, http.StatusUnauthorized, "Sesi login sudah kedaluwarsa, silakan login ulang")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses login")
+		return
+	}
+
+	user, err := h.Users.Get(r.Context(), challenge.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal mengambil data user")
+		return
+	}
+	if user.DisabledAt != nil {
+		utils.WriteError(w, http.StatusForbidden, accountDisabledMessage)
+		return
+	}
+
+	// Tebakan kode 2FA ikut dihitung di rate limit login yang sama dengan password
+	ip := ratelimit.ClientIP(r)
+	if !h.checkLoginLimit(w, ip, user.Email) {
+		return
+	}
+
+	// Percobaan dicatat sebelum kode diverifikasi, jadi request paralel tidak bisa menebak
+	// lebih dari maxLoginChallengeAttempts kali
+	err = h.Tokens.AttemptLoginChallenge(r.Context(), challenge.ID, maxLoginChallengeAttempts)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusUnauthorized, "Sesi login sudah kedaluwarsa, silakan login ulang")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses login")
+		return
+	}
+
+	ok, err := h.verifySecondFactor(r.Context(), user.ID, req.Code)
+	if err != nil && err != errTwoFactorNotEnabled {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memverifikasi kode 2FA")
+		return
+	}
+	if !ok {
+		h.recordLoginFailure(ip, user.Email)
+		utils.WriteError(w, http.StatusUnauthorized, "Kode 2FA salah")
+		return
+	}
+
+	// Challenge hanya bisa dipakai sekali, walau dua request datang bersamaan
+	err = h.Tokens.ConsumeLoginChallenge(r.Context(), challenge.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusUnauthorized, "Sesi login sudah kedaluwarsa, silakan login ulang")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memproses login")
+		return
+	}
+
+	resp, err := h.issueTokens(r.Context(), user)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal membuat token")
+		return
+	}
+
+	if err := h.LoginLimit.Succeeded(user.Email); err != nil {
+		log.Println("Gagal me-reset rate limit login:", err)
+	}
+
+	utils.WriteSuccess(w, "Login berhasil", resp)
+}
+
+// errTwoFactorNotEnabled dikembalikan verifySecondFactor jika user belum mengaktifkan 2FA
+var errTwoFactorNotEnabled = errors.New("2FA belum aktif")
+
+// createLoginChallenge membuat challenge token untuk tahap kedua login
+func (h *Handler) createLoginChallenge(ctx context.Context, userID int) (models.LoginChallengeResponse, error) {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return models.LoginChallengeResponse{}, err
+	}
+
+	if err := h.Tokens.CreateLoginChallenge(ctx, userID, hash, time.Now().UTC().Add(loginChallengeTTL)); err != nil {
+		return models.LoginChallengeResponse{}, err
+	}
+
+	return models.LoginChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresIn:         int(loginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// verifySecondFactor mengecek kode TOTP 6 digit atau recovery code milik user.
+// Kode TOTP yang sudah dipakai dan recovery code bekas tidak bisa dipakai lagi.
+func (h *Handler) verifySecondFactor(ctx context.Context, userID int, code string) (bool, error) {
+	tf, err := h.Users.TwoFactor(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && !tf.Enabled) {
+		return false, errTwoFactorNotEnabled
+	}
+	if err != nil {
+		return false, err
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == utils.TOTPDigits {
+		step, ok := utils.ValidateTOTP(tf.Secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return h.Users.UseTOTPStep(ctx, userID, step)
+	}
+
+	return h.Users.UseRecoveryCode(ctx, userID, utils.HashToken(normalizeRecoveryCode(code)))
+}
+
+// generateRecoveryCodes membuat recovery code baru beserta hash-nya. Hanya hash yang
+// disimpan; kode aslinya dikembalikan untuk ditampilkan sekali ke user.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := hex.EncodeToString(b)
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode membuang tanda strip dan spasi supaya format ketikan user tidak berpengaruh
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// totpIssuer adalah nama aplikasi yang tampil di aplikasi authenticator (env TOTP_ISSUER)
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "Notes"
+}
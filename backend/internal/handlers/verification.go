@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"notes-api/internal/mail"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"strings"
+	"time"
+)
+
+// Masa berlaku link verifikasi email
+const emailVerificationTTL = 48 * time.Hour
+
+// VerifyEmail menandai email user sudah terverifikasi memakai token dari link di email
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Token wajib diisi")
+		return
+	}
+
+	// Semua link verifikasi milik user ini tidak berlaku lagi setelah dipakai
+	_, err := h.Users.VerifyEmail(r.Context(), utils.HashToken(token))
+	if errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusBadRequest, "Link verifikasi tidak valid atau sudah kedaluwarsa")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "Gagal memverifikasi email")
+		return
+	}
+
+	utils.WriteSuccess(w, "Email berhasil diverifikasi", nil)
+}
+
+// ResendVerification mengirim ulang link verifikasi ke email yang belum terverifikasi.
+// Seperti ForgotPassword, response-nya selalu sama untuk email apa pun.
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req models.ResendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Data tidak valid")
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Email wajib diisi")
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		user, err := h.Users.GetByEmail(ctx, email)
+		if err != nil {
+			if !errors.Is(err, store.ErrNotFound) {
+				log.Println("Gagal mencari user untuk verifikasi email:", err)
+			}
+			return
+		}
+		if user.EmailVerifiedAt != nil {
+			return
+		}
+
+		if err := h.sendVerificationEmail(ctx, user.ID, email); err != nil {
+			log.Println("Gagal mengirim email verifikasi:", err)
+		}
+	}()
+
+	utils.WriteSuccess(w, "Jika email terdaftar dan belum terverifikasi, link verifikasi sudah dikirim", nil)
+}
+
+// sendVerificationEmail membuat token verifikasi baru dan mengirim link-nya ke email user
+func (h *Handler) sendVerificationEmail(ctx context.Context, userID int, email string) error {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := h.Users.CreateEmailVerification(ctx, userID, hash, time.Now().UTC().Add(emailVerificationTTL)); err != nil {
+		return err
+	}
+
+	link := apiLink("/api/verify-email", url.Values{"token": {token}})
+	return h.Mailer.Send(mail.Message{
+		To:      email,
+		Subject: "Verifikasi email Notes",
+		Body: "Terima kasih sudah mendaftar di Notes.\n\n" +
+			"Buka link berikut untuk memverifikasi email kamu (berlaku 48 jam):\n" + link + "\n\n" +
+			"Abaikan email ini jika kamu tidak merasa mendaftar.",
+	})
+}
@@ -0,0 +1,33 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogMailer hanya mencetak email ke log server, untuk development lokal
+type LogMailer struct{}
+
+// Send mencetak email ke log
+func (LogMailer) Send(msg Message) error {
+	log.Printf("📧 Email ke %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileMailer menyimpan setiap email sebagai file .eml di Dir, untuk development dan testing
+type FileMailer struct {
+	Dir string
+}
+
+// Send menulis email ke file baru di Dir
+func (m FileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	return os.WriteFile(filepath.Join(m.Dir, name), buildMessage("notes-api@localhost", msg), 0o644)
+}
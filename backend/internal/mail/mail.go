@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Message adalah satu email yang akan dikirim
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer mengirim email. Implementasi: SMTPMailer, FileMailer, LogMailer.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// FromEnv memilih implementasi Mailer dari env MAIL_DRIVER: smtp, file, atau log (default)
+func FromEnv() (Mailer, error) {
+	var m Mailer
+	driver := strings.ToLower(os.Getenv("MAIL_DRIVER"))
+	switch driver {
+	case "smtp":
+		smtp, err := NewSMTPMailerFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		m = smtp
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		m = FileMailer{Dir: dir}
+	case "", "log":
+		driver = "log"
+		m = LogMailer{}
+	default:
+		return nil, fmt.Errorf("MAIL_DRIVER %q tidak dikenal", driver)
+	}
+
+	log.Printf("Email dikirim lewat mailer %s", driver)
+	return m, nil
+}
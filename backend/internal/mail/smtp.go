@@ -0,0 +1,73 @@
+package mail
+
+import (
+	"fmt"
+	netmail "net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// SMTPMailer mengirim email lewat server SMTP (dengan STARTTLS jika didukung server)
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPMailerFromEnv membaca konfigurasi SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD dan MAIL_FROM
+func NewSMTPMailerFromEnv() (*SMTPMailer, error) {
+	m := &SMTPMailer{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("MAIL_FROM"),
+	}
+	if m.Port == "" {
+		m.Port = "587"
+	}
+	if m.Host == "" || m.From == "" {
+		return nil, fmt.Errorf("SMTP_HOST dan MAIL_FROM wajib diisi untuk MAIL_DRIVER=smtp")
+	}
+	return m, nil
+}
+
+// Send mengirim email ke server SMTP
+func (m *SMTPMailer) Send(msg Message) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	// MAIL_FROM boleh berformat "Nama <alamat>", envelope SMTP hanya butuh alamatnya
+	from, err := netmail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("MAIL_FROM tidak valid: %v", err)
+	}
+
+	addr := m.Host + ":" + m.Port
+	return smtp.SendMail(addr, auth, from.Address, []string{msg.To}, buildMessage(m.From, msg))
+}
+
+// buildMessage menyusun email plain text lengkap dengan header
+func buildMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + headerValue(from) + "\r\n")
+	b.WriteString("To: " + headerValue(msg.To) + "\r\n")
+	b.WriteString("Subject: " + headerValue(msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerValue membuang CR/LF supaya input user tidak bisa menyisipkan header baru
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
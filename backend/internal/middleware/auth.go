@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"notes-api/internal/revocation"
+	"notes-api/internal/store"
 	"notes-api/internal/utils"
 	"strings"
 )
@@ -12,38 +15,73 @@ type contextKey string
 
 const UserIDKey contextKey = "userID"
 
-// Auth middleware untuk validasi JWT token
-func Auth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Ambil token dari header Authorization
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			utils.WriteError(w, http.StatusUnauthorized, "Token tidak ditemukan")
-			return
-		}
-
-		// Format: "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			utils.WriteError(w, http.StatusUnauthorized, "Format token salah")
-			return
-		}
-
-		tokenString := parts[1]
-
-		// Validasi token
-		claims, err := utils.ValidateToken(tokenString)
-		if err != nil {
-			utils.WriteError(w, http.StatusUnauthorized, "Token tidak valid")
-			return
-		}
-
-		// Simpan user ID ke context
-		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-
-		// Lanjut ke handler berikutnya
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// ClaimsKey untuk menyimpan claims JWT lengkap di context (dipakai logout)
+const ClaimsKey contextKey = "claims"
+
+// Auth middleware untuk validasi JWT token atau personal access token.
+// tokens dipakai untuk mencari personal access token dan mengecek token yang sudah dicabut.
+func Auth(tokens store.TokenStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Ambil token dari header Authorization
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				utils.WriteError(w, http.StatusUnauthorized, "Token tidak ditemukan")
+				return
+			}
+
+			// Format: "Bearer <token>"
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				utils.WriteError(w, http.StatusUnauthorized, "Format token salah")
+				return
+			}
+
+			tokenString := parts[1]
+
+			// Personal access token: hanya user ID dan scope yang disimpan ke context
+			if strings.HasPrefix(tokenString, AccessTokenPrefix) {
+				userID, scopes, err := tokens.UseAccessToken(r.Context(), utils.HashToken(tokenString))
+				if errors.Is(err, store.ErrNotFound) {
+					utils.WriteError(w, http.StatusUnauthorized, "Token tidak valid")
+					return
+				}
+				if err != nil {
+					utils.WriteError(w, http.StatusInternalServerError, "Gagal memvalidasi token")
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), UserIDKey, userID)
+				ctx = context.WithValue(ctx, ScopesKey, scopes)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			// Validasi token
+			claims, err := utils.ValidateToken(tokenString)
+			if err != nil {
+				utils.WriteError(w, http.StatusUnauthorized, "Token tidak valid")
+				return
+			}
+
+			// Cek apakah token sudah dicabut (logout, logout-all, ganti password)
+			if err := revocation.Check(r.Context(), tokens, claims); err != nil {
+				if err == revocation.ErrRevoked {
+					utils.WriteError(w, http.StatusUnauthorized, "Token sudah tidak berlaku")
+					return
+				}
+				utils.WriteError(w, http.StatusInternalServerError, "Gagal memvalidasi token")
+				return
+			}
+
+			// Simpan user ID dan claims ke context
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, ClaimsKey, claims)
+
+			// Lanjut ke handler berikutnya
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 // GetUserID mengambil user ID dari context
@@ -54,3 +92,9 @@ func GetUserID(r *http.Request) int {
 	}
 	return userID
 }
+
+// GetClaims mengambil claims JWT dari context
+func GetClaims(r *http.Request) *utils.Claims {
+	claims, _ := r.Context().Value(ClaimsKey).(*utils.Claims)
+	return claims
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"notes-api/internal/utils"
+)
+
+// Scope yang bisa diberikan ke personal access token
+const (
+	ScopeNotesRead  = "notes:read"  // baca catatan, folder, tag, revisi, trash dan search
+	ScopeNotesWrite = "notes:write" // ubah catatan dan folder
+	ScopeTagsWrite  = "tags:write"  // ubah tag dan pasang/lepas tag di catatan
+)
+
+// AllScopes adalah daftar semua scope yang valid
+var AllScopes = []string{ScopeNotesRead, ScopeNotesWrite, ScopeTagsWrite}
+
+// AccessTokenPrefix menandai personal access token supaya bisa dibedakan dari JWT
+const AccessTokenPrefix = "pat_"
+
+// ScopesKey untuk menyimpan scope personal access token di context.
+// Tidak ada di context berarti request memakai JWT (akses penuh).
+const ScopesKey contextKey = "scopes"
+
+// ValidScope mengecek apakah scope dikenal
+func ValidScope(scope string) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GetScopes mengambil scope personal access token dari context.
+// ok false berarti request memakai JWT biasa.
+func GetScopes(r *http.Request) (scopes []string, ok bool) {
+	scopes, ok = r.Context().Value(ScopesKey).([]string)
+	return scopes, ok
+}
+
+// HasScope mengecek apakah request boleh memakai scope tertentu (JWT selalu boleh)
+func HasScope(r *http.Request, scope string) bool {
+	scopes, ok := GetScopes(r)
+	return !ok || hasScope(scopes, scope)
+}
+
+// RequireScope menolak request dengan personal access token yang tidak punya scope ini.
+// Request dengan JWT selalu lolos. Harus dipasang setelah Auth.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasScope(r, scope) {
+				utils.WriteError(w, http.StatusForbidden, "Token tidak punya scope "+scope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SessionOnly menolak personal access token, untuk route akun (profil, password, 2FA, token)
+// yang hanya boleh diakses lewat login biasa. Harus dipasang setelah Auth.
+func SessionOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetScopes(r); ok {
+			utils.WriteError(w, http.StatusForbidden, "Endpoint ini tidak bisa diakses dengan personal access token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
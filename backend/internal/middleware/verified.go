@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"os"
+	"strings"
+)
+
+// Hak akses akun yang emailnya belum diverifikasi (env UNVERIFIED_ACCESS)
+const (
+	UnverifiedFull     = "full"     // boleh login dan menulis seperti biasa
+	UnverifiedReadOnly = "readonly" // boleh login tapi hanya bisa membaca
+	UnverifiedNone     = "none"     // tidak boleh login sama sekali
+)
+
+// UnverifiedAccess membaca env UNVERIFIED_ACCESS, default readonly
+func UnverifiedAccess() string {
+	switch mode := strings.ToLower(os.Getenv("UNVERIFIED_ACCESS")); mode {
+	case UnverifiedFull, UnverifiedNone:
+		return mode
+	default:
+		return UnverifiedReadOnly
+	}
+}
+
+// RequireVerifiedForWrite menolak request yang mengubah data (selain GET/HEAD/OPTIONS)
+// dari user yang emailnya belum diverifikasi, jika UNVERIFIED_ACCESS=readonly.
+// Harus dipasang setelah Auth.
+func RequireVerifiedForWrite(users store.UserStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if UnverifiedAccess() == UnverifiedFull {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := users.Get(r.Context(), GetUserID(r))
+			if err != nil {
+				utils.WriteError(w, http.StatusInternalServerError, "Gagal memeriksa status verifikasi email")
+				return
+			}
+
+			if user.EmailVerifiedAt == nil {
+				utils.WriteError(w, http.StatusForbidden, "Verifikasi email kamu dulu untuk bisa mengubah data")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
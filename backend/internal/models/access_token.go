@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// PersonalAccessToken adalah token berumur panjang untuk script/CI, tanpa nilai token aslinya
+type PersonalAccessToken struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"token_prefix"` // beberapa karakter awal token, untuk dikenali user
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAccessTokenRequest untuk membuat personal access token baru
+type CreateAccessTokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"` // opsional, null = tidak kedaluwarsa
+}
+
+// CreateAccessTokenResponse berisi token asli yang hanya ditampilkan sekali
+type CreateAccessTokenResponse struct {
+	Token string `json:"token"`
+	PersonalAccessToken
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// UserExportVersion adalah versi format file export-user; import menolak versi lain
+const UserExportVersion = 1
+
+// UserExport berisi semua data milik satu user untuk dipindah ke database lain lewat
+// perintah `notes-api export-user` dan `import-user`. ID di dalam file hanya dipakai
+// untuk relasi antar data di file ini; saat import semua data mendapat ID baru.
+//
+// Sesi, personal access token, 2FA dan akun SSO yang terhubung tidak ikut di-export.
+type UserExport struct {
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	User       ExportUser     `json:"user"`
+	Folders    []ExportFolder `json:"folders"`
+	Tags       []ExportTag    `json:"tags"`
+	Notes      []ExportNote   `json:"notes"`
+}
+
+// ExportUser berisi data akun; password tetap dalam bentuk hash
+type ExportUser struct {
+	Username          string     `json:"username"`
+	Email             string     `json:"email"`
+	FullName          string     `json:"full_name"`
+	PasswordHash      string     `json:"password_hash"`
+	CreatedAt         time.Time  `json:"created_at"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at"`
+	RevisionRetention int        `json:"revision_retention"`
+}
+
+// ExportFolder adalah satu folder, termasuk yang ada di trash
+type ExportFolder struct {
+	ID        int        `json:"id"`
+	ParentID  *int       `json:"parent_id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ExportTag adalah satu tag
+type ExportTag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportNote adalah satu catatan beserta ID tag dan riwayat revisinya
+type ExportNote struct {
+	ID         int              `json:"id"`
+	FolderID   *int             `json:"folder_id"`
+	Title      string           `json:"title"`
+	Content    string           `json:"content"`
+	IsFavorite bool             `json:"is_favorite"`
+	Version    int              `json:"version"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	DeletedAt  *time.Time       `json:"deleted_at,omitempty"`
+	TagIDs     []int            `json:"tag_ids"`
+	Revisions  []ExportRevision `json:"revisions"`
+}
+
+// ExportRevision adalah satu revisi lama dari catatan
+type ExportRevision struct {
+	Revision  int       `json:"revision"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -4,8 +4,16 @@ import "time"
 
 // Folder struct untuk kategorisasi catatan
 type Folder struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	ParentID  *int       `json:"parent_id"` // pointer karena folder root tidak punya parent
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // terisi jika folder ada di trash
+	Children  []*Folder  `json:"children,omitempty"`   // hanya diisi oleh endpoint tree
+}
+
+// MoveFolderRequest untuk memindahkan folder ke parent lain (null = ke root)
+type MoveFolderRequest struct {
+	ParentID *int `json:"parent_id"`
 }
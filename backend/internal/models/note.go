@@ -4,16 +4,18 @@ import "time"
 
 // Note struct untuk representasi catatan
 type Note struct {
-	ID         int       `json:"id"`
-	UserID     int       `json:"user_id"`
-	FolderID   *int      `json:"folder_id"` // pointer karena bisa NULL
-	FolderName string    `json:"folder_name"`
-	Title      string    `json:"title"`
-	Content    string    `json:"content"`
-	IsFavorite bool      `json:"is_favorite"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	Tags       []Tag     `json:"tags,omitempty"` // Include tags
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	FolderID   *int       `json:"folder_id"` // pointer karena bisa NULL
+	FolderName string     `json:"folder_name"`
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	IsFavorite bool       `json:"is_favorite"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Version    int        `json:"version"`              // naik setiap catatan diubah, dipakai untuk ETag
+	Tags       []Tag      `json:"tags,omitempty"`       // Include tags
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"` // terisi jika catatan ada di trash
 }
 
 // NoteWithTags untuk note yang sudah include tags-nya
@@ -21,3 +23,26 @@ type NoteWithTags struct {
 	Note
 	Tags []Tag `json:"tags"`
 }
+
+// SearchResult untuk hasil pencarian full-text beserta skor relevansinya
+type SearchResult struct {
+	Note
+	Score          float64 `json:"score"`
+	TitleHighlight string  `json:"title_highlight"` // judul dengan kata yang cocok dibungkus <mark>
+	Snippet        string  `json:"snippet"`         // potongan isi catatan dengan kata yang cocok dibungkus <mark>
+}
+
+// BulkNoteRequest untuk menjalankan satu aksi ke banyak catatan sekaligus
+type BulkNoteRequest struct {
+	NoteIDs  []int  `json:"note_ids"`
+	Action   string `json:"action"`    // move, add_tags, remove_tags, favorite, unfavorite, delete
+	FolderID *int   `json:"folder_id"` // untuk action move, null = keluarkan dari folder
+	TagIDs   []int  `json:"tag_ids"`   // untuk action add_tags dan remove_tags
+}
+
+// BulkNoteResult untuk hasil aksi bulk per catatan
+type BulkNoteResult struct {
+	ID      int    `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// NoteRevision struct untuk satu versi lama dari sebuah catatan
+type NoteRevision struct {
+	ID        int       `json:"id"`
+	NoteID    int       `json:"note_id"`
+	Revision  int       `json:"revision"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RevisionDiff untuk hasil perbandingan dua revisi dalam format unified diff
+type RevisionDiff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Diff string `json:"diff"`
+}
+
+// RevisionSettings untuk pengaturan retention revisi milik user
+type RevisionSettings struct {
+	Retention int `json:"retention"` // jumlah revisi terakhir yang disimpan per catatan
+}
@@ -10,3 +10,8 @@ type Tag struct {
 	CreatedAt time.Time `json:"created_at"`
 	NoteCount int       `json:"note_count"`
 }
+
+// MergeTagRequest untuk menggabungkan tag sumber ke tag tujuan
+type MergeTagRequest struct {
+	TargetID int `json:"target_id"`
+}
@@ -0,0 +1,36 @@
+package models
+
+// TwoFactorSetupResponse berisi secret TOTP baru yang belum aktif sampai dikonfirmasi
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorCodeRequest berisi kode dari aplikasi authenticator (atau recovery code)
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorDisableRequest untuk mematikan 2FA, butuh password dan kode 2FA
+type TwoFactorDisableRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// RecoveryCodesResponse berisi recovery code yang hanya ditampilkan sekali
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginChallengeResponse dikembalikan Login jika user mengaktifkan 2FA
+type LoginChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"` // masa berlaku challenge token dalam detik
+}
+
+// LoginTwoFactorRequest untuk menukar challenge token + kode 2FA dengan JWT
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
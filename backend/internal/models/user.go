@@ -10,6 +10,12 @@ type User struct {
 	PasswordHash string    `json:"-"` // tidak di-return ke client
 	FullName     string    `json:"full_name"`
 	CreatedAt    time.Time `json:"created_at"`
+
+	EmailVerifiedAt  *time.Time `json:"email_verified_at"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+
+	// DisabledAt diisi jika akun dinonaktifkan admin lewat CLI; user tidak bisa login
+	DisabledAt *time.Time `json:"-"`
 }
 
 // RegisterRequest untuk data registrasi user baru
@@ -28,6 +34,48 @@ type LoginRequest struct {
 
 // LoginResponse untuk response setelah login berhasil
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // masa berlaku access token dalam detik
+	User         User   `json:"user"`
+}
+
+// RefreshRequest untuk menukar refresh token dengan access token baru
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// UpdateProfileRequest untuk mengubah profil user yang sedang login
+type UpdateProfileRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+}
+
+// ChangePasswordRequest untuk mengganti password user yang sedang login
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// ForgotPasswordRequest untuk meminta link reset password
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest untuk mengganti password dengan token dari email
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// ResendVerificationRequest untuk meminta ulang link verifikasi email
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+// OIDCCallbackRequest berisi code dan state dari redirect identity provider ke frontend
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
 }
@@ -0,0 +1,77 @@
+package oidc
+
+import (
+	"errors"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ClaimMapping menentukan claim ID token mana yang diisi ke field models.User
+type ClaimMapping struct {
+	Username      string
+	FullName      string
+	Email         string
+	EmailVerified string
+}
+
+// Identity adalah data user dari ID token setelah claim mapping
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	FullName      string
+}
+
+// ClaimMappingFromEnv membaca OIDC_CLAIM_USERNAME, OIDC_CLAIM_FULL_NAME, OIDC_CLAIM_EMAIL
+// dan OIDC_CLAIM_EMAIL_VERIFIED, dengan default claim standar OIDC
+func ClaimMappingFromEnv() ClaimMapping {
+	return ClaimMapping{
+		Username:      envOr("OIDC_CLAIM_USERNAME", "preferred_username"),
+		FullName:      envOr("OIDC_CLAIM_FULL_NAME", "name"),
+		Email:         envOr("OIDC_CLAIM_EMAIL", "email"),
+		EmailVerified: envOr("OIDC_CLAIM_EMAIL_VERIFIED", "email_verified"),
+	}
+}
+
+// MapClaims mengubah claim ID token menjadi Identity sesuai mapping
+func (m ClaimMapping) MapClaims(claims jwt.MapClaims) (Identity, error) {
+	id := Identity{
+		Email:    stringClaim(claims, m.Email),
+		Username: stringClaim(claims, m.Username),
+		FullName: stringClaim(claims, m.FullName),
+	}
+	id.Issuer, _ = claims.GetIssuer()
+	id.Subject, _ = claims.GetSubject()
+
+	if id.Subject == "" {
+		return Identity{}, errors.New("ID token tidak berisi sub")
+	}
+
+	// Sebagian provider mengirim email_verified sebagai string "true"
+	switch v := claims[m.EmailVerified].(type) {
+	case bool:
+		id.EmailVerified = v
+	case string:
+		id.EmailVerified = v == "true"
+	}
+
+	return id, nil
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	if name == "" {
+		return ""
+	}
+	s, _ := claims[name].(string)
+	return s
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
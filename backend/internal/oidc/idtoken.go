@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWKS provider di-fetch ulang paling sering sekali per menit (saat ada kid yang tidak dikenal)
+const jwksRefreshInterval = time.Minute
+
+// Algoritma ID token yang diterima; "none" dan HMAC sengaja tidak ada
+var idTokenAlgs = []string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "EdDSA"}
+
+type keySet struct {
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// VerifyIDToken memvalidasi signature ID token dengan JWKS provider, lalu issuer, audience,
+// masa berlaku dan nonce. Return semua claim di dalam token.
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (jwt.MapClaims, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(idTokenAlgs),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.Config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	_, err = parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.verificationKey(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ID token tidak valid: %v", err)
+	}
+
+	// Jika ada beberapa audience, azp wajib berisi client kita (OIDC Core 3.1.3.7)
+	if aud, _ := claims.GetAudience(); len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.Config.ClientID {
+			return nil, errors.New("ID token: azp tidak cocok")
+		}
+	}
+
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, errors.New("ID token: nonce tidak cocok")
+	}
+
+	return claims, nil
+}
+
+// verificationKey mencari public key berdasarkan kid, fetch ulang JWKS jika kid belum dikenal
+// (provider baru saja merotasi kunci)
+func (p *Provider) verificationKey(ctx context.Context, kid string) (interface{}, error) {
+	p.mu.Lock()
+	cached := p.keys
+	p.mu.Unlock()
+
+	if cached != nil {
+		if key, ok := lookupKey(cached, kid); ok {
+			return key, nil
+		}
+		if time.Since(cached.fetchedAt) < jwksRefreshInterval {
+			return nil, fmt.Errorf("kid %q tidak dikenal", kid)
+		}
+	}
+
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.getJSON(ctx, d.JWKSURI, &doc); err != nil {
+		return nil, fmt.Errorf("gagal mengambil JWKS provider: %v", err)
+	}
+
+	set := &keySet{keys: map[string]interface{}{}, fetchedAt: time.Now()}
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, err := k.publicKey(); err == nil {
+			set.keys[k.Kid] = key
+		}
+	}
+
+	p.mu.Lock()
+	p.keys = set
+	p.mu.Unlock()
+
+	if key, ok := lookupKey(set, kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("kid %q tidak dikenal", kid)
+}
+
+// lookupKey mencari kunci berdasarkan kid. Token tanpa kid diterima jika provider hanya punya satu kunci.
+func lookupKey(set *keySet, kid string) (interface{}, bool) {
+	if kid == "" && len(set.keys) == 1 {
+		for _, key := range set.keys {
+			return key, true
+		}
+	}
+	key, ok := set.keys[kid]
+	return key, ok
+}
+
+// publicKey mengubah JWK menjadi public key Go
+func (k jwk) publicKey() (interface{}, error) {
+	b64 := base64.RawURLEncoding
+	switch k.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("curve %q tidak didukung", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("curve %q tidak didukung", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("panjang kunci Ed25519 tidak valid")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("kty %q tidak didukung", k.Kty)
+}
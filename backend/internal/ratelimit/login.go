@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoginLimiter membatasi login per IP (longgar, melawan satu mesin yang mencoba banyak akun)
+// dan per akun (ketat, melawan tebak password satu akun dari banyak IP)
+type LoginLimiter struct {
+	IP      *Limiter
+	Account *Limiter
+}
+
+// NewLoginLimiter membuat LoginLimiter yang menyimpan hitungan gagal di store
+func NewLoginLimiter(store Store) *LoginLimiter {
+	return &LoginLimiter{
+		IP:      &Limiter{Store: store, Policy: ipPolicy(), Prefix: "login-ip:"},
+		Account: &Limiter{Store: store, Policy: accountPolicy(), Prefix: "login-account:"},
+	}
+}
+
+// ErrStoreUnknown dikembalikan StoreFromEnv jika RATE_LIMIT_STORE tidak dikenal
+var ErrStoreUnknown = errors.New("RATE_LIMIT_STORE harus memory atau database")
+
+// StoreFromEnv memilih store dari env RATE_LIMIT_STORE: memory (default) atau database,
+// yaitu store login_attempts milik backend database yang sedang dipakai
+func StoreFromEnv(database Store) (Store, error) {
+	switch os.Getenv("RATE_LIMIT_STORE") {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "database":
+		return database, nil
+	default:
+		return nil, ErrStoreUnknown
+	}
+}
+
+// Check mengembalikan berapa lama client harus menunggu sebelum boleh mencoba login lagi
+func (l *LoginLimiter) Check(ip, account string) (time.Duration, error) {
+	ipWait, err := l.IP.Check(ip)
+	if err != nil {
+		return 0, err
+	}
+	accountWait, err := l.Account.Check(normalizeAccount(account))
+	if err != nil {
+		return 0, err
+	}
+
+	if ipWait > accountWait {
+		return ipWait, nil
+	}
+	return accountWait, nil
+}
+
+// Failed mencatat login gagal untuk IP dan akun
+func (l *LoginLimiter) Failed(ip, account string) error {
+	if _, err := l.IP.Fail(ip); err != nil {
+		return err
+	}
+	_, err := l.Account.Fail(normalizeAccount(account))
+	return err
+}
+
+// Succeeded menghapus hitungan gagal akun. Hitungan IP sengaja tidak di-reset
+// supaya login ke akun sendiri tidak bisa dipakai untuk menghapus jejak tebakan ke akun lain.
+func (l *LoginLimiter) Succeeded(account string) error {
+	return l.Account.Reset(normalizeAccount(account))
+}
+
+// ClientIP mengambil IP client dari request. Header X-Forwarded-For hanya dipercaya jika
+// TRUST_PROXY=true (server di belakang reverse proxy), dan yang dipakai adalah entry terakhir
+// karena itu yang ditambahkan proxy kita sendiri.
+func ClientIP(r *http.Request) string {
+	if os.Getenv("TRUST_PROXY") == "true" {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			parts := strings.Split(xff, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RetryAfterSeconds membulatkan durasi ke atas dalam detik untuk header Retry-After
+func RetryAfterSeconds(d time.Duration) string {
+	secs := int((d + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return strconv.Itoa(secs)
+}
+
+func ipPolicy() Policy {
+	return Policy{
+		FreeAttempts:    20,
+		BaseDelay:       time.Second,
+		MaxDelay:        5 * time.Minute,
+		LockoutAfter:    100,
+		LockoutDuration: time.Hour,
+		ResetAfter:      time.Hour,
+	}
+}
+
+// accountPolicy memakai env LOGIN_MAX_ATTEMPTS (default 10) dan LOGIN_LOCKOUT_DURATION (default 15m)
+func accountPolicy() Policy {
+	maxAttempts, err := strconv.Atoi(os.Getenv("LOGIN_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = 10
+	}
+
+	lockout, err := time.ParseDuration(os.Getenv("LOGIN_LOCKOUT_DURATION"))
+	if err != nil || lockout <= 0 {
+		lockout = 15 * time.Minute
+	}
+
+	return Policy{
+		FreeAttempts:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutAfter:    maxAttempts,
+		LockoutDuration: lockout,
+		ResetAfter:      time.Hour,
+	}
+}
+
+func normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
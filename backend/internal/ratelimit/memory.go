@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry di MemoryStore yang tidak disentuh selama ini dibuang
+const memoryEntryTTL = 24 * time.Hour
+
+// MemoryStore menyimpan State di memori proses. Cocok untuk satu instance server;
+// untuk beberapa instance pakai DBStore supaya hitungan dibagi bersama.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]State
+	lastSweep time.Time
+}
+
+// NewMemoryStore membuat MemoryStore kosong
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]State{}}
+}
+
+// Get mengambil State untuk key (State kosong jika belum ada)
+func (m *MemoryStore) Get(key string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.entries[key], nil
+}
+
+// Update mengubah State untuk key di bawah lock
+func (m *MemoryStore) Update(key string, fn func(s *State)) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+
+	s := m.entries[key]
+	fn(&s)
+	m.entries[key] = s
+	return s, nil
+}
+
+// Delete menghapus State untuk key
+func (m *MemoryStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+// sweep membuang entry lama supaya map tidak tumbuh terus. Harus dipanggil dengan lock.
+func (m *MemoryStore) sweep() {
+	now := time.Now()
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+
+	for key, s := range m.entries {
+		if now.Sub(s.LastFailure) > memoryEntryTTL && !s.BlockedUntil.After(now) {
+			delete(m.entries, key)
+		}
+	}
+}
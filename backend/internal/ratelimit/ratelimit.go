@@ -0,0 +1,95 @@
+package ratelimit
+
+import "time"
+
+// State adalah catatan percobaan gagal untuk satu key (IP atau akun)
+type State struct {
+	Failures     int
+	LastFailure  time.Time
+	BlockedUntil time.Time
+}
+
+// Store menyimpan State per key. Update harus atomic supaya beberapa request
+// bersamaan (atau beberapa instance server) tidak saling menimpa hitungan.
+type Store interface {
+	Get(key string) (State, error)
+	Update(key string, fn func(s *State)) (State, error)
+	Delete(key string) error
+}
+
+// Policy mengatur kapan sebuah key mulai diperlambat dan dikunci
+type Policy struct {
+	FreeAttempts    int           // jumlah gagal sebelum backoff mulai berlaku
+	BaseDelay       time.Duration // jeda setelah gagal pertama melewati FreeAttempts, lalu dikali 2 setiap gagal
+	MaxDelay        time.Duration // batas atas backoff
+	LockoutAfter    int           // jumlah gagal sampai key dikunci (0 = tidak pernah)
+	LockoutDuration time.Duration // lama kunci
+	ResetAfter      time.Duration // hitungan gagal dilupakan jika tidak ada kegagalan selama ini
+}
+
+// blockFor menghitung berapa lama key diblokir setelah gagal ke-failures
+func (p Policy) blockFor(failures int) time.Duration {
+	if p.LockoutAfter > 0 && failures >= p.LockoutAfter {
+		return p.LockoutDuration
+	}
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// Limiter menerapkan Policy di atas Store. Prefix memisahkan key antar limiter di store yang sama.
+type Limiter struct {
+	Store  Store
+	Policy Policy
+	Prefix string
+}
+
+// Check mengembalikan sisa waktu blokir untuk key, 0 jika boleh mencoba
+func (l *Limiter) Check(key string) (time.Duration, error) {
+	s, err := l.Store.Get(l.Prefix + key)
+	if err != nil {
+		return 0, err
+	}
+	return remaining(s.BlockedUntil, time.Now()), nil
+}
+
+// Fail mencatat satu percobaan gagal dan mengembalikan lama blokir yang berlaku setelahnya
+func (l *Limiter) Fail(key string) (time.Duration, error) {
+	now := time.Now().UTC()
+
+	s, err := l.Store.Update(l.Prefix+key, func(s *State) {
+		if l.Policy.ResetAfter > 0 && now.Sub(s.LastFailure) > l.Policy.ResetAfter {
+			s.Failures = 0
+		}
+		s.Failures++
+		s.LastFailure = now
+		if d := l.Policy.blockFor(s.Failures); d > 0 {
+			s.BlockedUntil = now.Add(d)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return remaining(s.BlockedUntil, now), nil
+}
+
+// Reset menghapus catatan gagal untuk key (dipanggil setelah login berhasil)
+func (l *Limiter) Reset(key string) error {
+	return l.Store.Delete(l.Prefix + key)
+}
+
+func remaining(until, now time.Time) time.Duration {
+	if until.After(now) {
+		return until.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"notes-api/internal/store"
+	"notes-api/internal/utils"
+	"time"
+)
+
+// ErrRevoked dikembalikan jika token sudah dicabut
+var ErrRevoked = errors.New("token sudah dicabut")
+
+// Check memastikan access token belum dicabut: jti tidak ada di daftar token yang dicabut
+// dan token tidak di-issue sebelum tokens_valid_after milik user. Keduanya dalam detik,
+// jadi token yang di-issue di detik yang sama dengan logout-all tetap valid.
+func Check(ctx context.Context, tokens store.TokenStore, claims *utils.Claims) error {
+	revoked, validAfter, err := tokens.AccessTokenStatus(ctx, claims.ID, claims.UserID)
+	if errors.Is(err, store.ErrNotFound) {
+		return ErrRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		return ErrRevoked
+	}
+
+	if validAfter != nil {
+		if claims.IssuedAt == nil || claims.IssuedAt.Time.Before(validAfter.Truncate(time.Second)) {
+			return ErrRevoked
+		}
+	}
+
+	return nil
+}
+
+// Revoke mencabut satu access token berdasarkan jti sampai token tersebut kedaluwarsa
+func Revoke(ctx context.Context, tokens store.TokenStore, claims *utils.Claims) error {
+	if claims == nil || claims.ID == "" {
+		return nil
+	}
+
+	expiresAt := time.Now().UTC().Add(utils.AccessTokenTTL())
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time.UTC()
+	}
+
+	return tokens.RevokeAccessToken(ctx, claims.ID, claims.UserID, expiresAt)
+}
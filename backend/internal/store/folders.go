@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"notes-api/internal/models"
+)
+
+// FolderStore menyimpan folder (bertingkat) milik user
+type FolderStore interface {
+	// List mengambil semua folder aktif milik user, terbaru dulu
+	List(ctx context.Context, userID int) ([]models.Folder, error)
+
+	// Children memetakan setiap folder aktif milik user ke daftar ID sub-folder langsungnya
+	Children(ctx context.Context, userID int) (map[int][]int, error)
+
+	// Create menyimpan folder baru lalu mengisi ID dan waktu pembuatannya.
+	// ErrFolderNotFound jika ParentID bukan folder aktif milik user.
+	Create(ctx context.Context, folder *models.Folder) error
+
+	// Update mengunci folder, memanggil fn untuk mengubah nama atau parent-nya, lalu
+	// menyimpan hasilnya. Jika parent berubah, pemindahan divalidasi dengan CheckMove.
+	Update(ctx context.Context, userID, folderID int, fn func(folder *models.Folder) error) (models.Folder, error)
+
+	// Delete memindahkan folder beserta catatannya ke trash. Jika cascade, semua sub-folder
+	// dan catatannya ikut; jika tidak, sub-folder dipindah ke parent folder yang dihapus.
+	// Return jumlah folder yang dipindah ke trash.
+	Delete(ctx context.Context, userID, folderID int, cascade bool) (int, error)
+}
+
+// CheckMove memvalidasi pemindahan folder ke parentID (nil = root) berdasarkan peta
+// hasil Children. ErrNotFound jika folder tidak ada, ErrFolderNotFound jika parent
+// tidak ada, dan ErrFolderCycle jika parent adalah folder itu sendiri atau turunannya.
+func CheckMove(children map[int][]int, folderID int, parentID *int) error {
+	if _, ok := children[folderID]; !ok {
+		return ErrNotFound
+	}
+
+	if parentID == nil {
+		return nil
+	}
+
+	if _, ok := children[*parentID]; !ok {
+		return ErrFolderNotFound
+	}
+
+	for _, id := range SubtreeIDs(children, folderID) {
+		if id == *parentID {
+			return ErrFolderCycle
+		}
+	}
+
+	return nil
+}
+
+// SubtreeIDs mengembalikan rootID beserta semua ID turunannya
+func SubtreeIDs(children map[int][]int, rootID int) []int {
+	ids := []int{rootID}
+	seen := map[int]bool{rootID: true}
+
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"notes-api/internal/store"
+	"notes-api/internal/store/memory"
+	"notes-api/internal/store/storetest"
+	"testing"
+)
+
+// Store in-memory dipakai test HTTP sebagai pengganti database, jadi perilakunya harus
+// sama dengan sqlstore
+func TestContract(t *testing.T) {
+	storetest.RunContract(t, func(testing.TB) *store.Store { return memory.New() })
+}
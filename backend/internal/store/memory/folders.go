@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"sort"
+)
+
+type folderStore struct {
+	*data
+}
+
+func (s *folderStore) List(ctx context.Context, userID int) ([]models.Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folders := []models.Folder{}
+	for _, id := range sortedKeys(s.folders) {
+		if folder := s.activeFolder(userID, id); folder != nil {
+			folders = append(folders, copyFolder(folder))
+		}
+	}
+
+	// Terbaru dulu, seperti ORDER BY created_at DESC
+	sort.SliceStable(folders, func(i, j int) bool {
+		if !folders[i].CreatedAt.Equal(folders[j].CreatedAt) {
+			return folders[i].CreatedAt.After(folders[j].CreatedAt)
+		}
+		return folders[i].ID > folders[j].ID
+	})
+	return folders, nil
+}
+
+func (s *folderStore) Children(ctx context.Context, userID int) (map[int][]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.folderChildren(userID), nil
+}
+
+func (s *folderStore) Create(ctx context.Context, folder *models.Folder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if folder.ParentID != nil && s.activeFolder(folder.UserID, *folder.ParentID) == nil {
+		return store.ErrFolderNotFound
+	}
+
+	folder.ID = s.nextID("folders")
+	folder.CreatedAt = now()
+
+	saved := copyFolder(folder)
+	s.folders[folder.ID] = &saved
+	return nil
+}
+
+func (s *folderStore) Update(ctx context.Context, userID, folderID int, fn func(folder *models.Folder) error) (models.Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.activeFolder(userID, folderID)
+	if current == nil {
+		return models.Folder{}, store.ErrNotFound
+	}
+
+	folder := copyFolder(current)
+	if err := fn(&folder); err != nil {
+		return models.Folder{}, err
+	}
+
+	if !sameID(current.ParentID, folder.ParentID) {
+		if err := store.CheckMove(s.folderChildren(userID), folderID, folder.ParentID); err != nil {
+			return models.Folder{}, err
+		}
+	}
+
+	current.Name = folder.Name
+	current.ParentID = copyInt(folder.ParentID)
+	return copyFolder(current), nil
+}
+
+func (s *folderStore) Delete(ctx context.Context, userID, folderID int, cascade bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folder := s.activeFolder(userID, folderID)
+	if folder == nil {
+		return 0, store.ErrNotFound
+	}
+
+	// deleted_at yang sama dipakai untuk folder dan catatannya supaya bisa di-restore bersamaan
+	deletedAt := now()
+
+	ids := []int{folderID}
+	if cascade {
+		ids = store.SubtreeIDs(s.folderChildren(userID), folderID)
+	} else {
+		for _, child := range s.folders {
+			if child.UserID == userID && child.DeletedAt == nil && child.ParentID != nil && *child.ParentID == folderID {
+				child.ParentID = copyInt(folder.ParentID)
+			}
+		}
+	}
+
+	trashed := map[int]bool{}
+	for _, id := range ids {
+		trashed[id] = true
+		at := deletedAt
+		s.folders[id].DeletedAt = &at
+	}
+
+	for _, note := range s.notes {
+		if note.UserID == userID && note.DeletedAt == nil && note.FolderID != nil && trashed[*note.FolderID] {
+			at := deletedAt
+			note.DeletedAt = &at
+		}
+	}
+
+	return len(ids), nil
+}
+
+func copyFolder(folder *models.Folder) models.Folder {
+	result := *folder
+	result.ParentID = copyInt(folder.ParentID)
+	result.DeletedAt = copyTime(folder.DeletedAt)
+	result.Children = nil
+	return result
+}
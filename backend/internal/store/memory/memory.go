@@ -0,0 +1,248 @@
+// Package memory mengimplementasikan interface di package store tanpa database:
+// semua data disimpan di map dan dijaga satu mutex. Dipakai untuk menjalankan dan
+// menguji HTTP layer tanpa MySQL; data hilang saat proses berhenti.
+package memory
+
+import (
+	"notes-api/internal/models"
+	"notes-api/internal/ratelimit"
+	"notes-api/internal/store"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Nilai default kolom users.revision_retention
+const defaultRevisionRetention = 50
+
+// New membuat store kosong
+func New() *store.Store {
+	d := &data{
+		seq:                map[string]int{},
+		users:              map[int]*user{},
+		notes:              map[int]*models.Note{},
+		folders:            map[int]*models.Folder{},
+		tags:               map[int]*models.Tag{},
+		noteTags:           map[noteTag]bool{},
+		revisions:          map[int][]revision{},
+		refreshTokens:      map[string]*store.RefreshToken{},
+		revokedTokens:      map[string]time.Time{},
+		accessTokens:       map[int]*accessToken{},
+		emailVerifications: map[string]expiringToken{},
+		emailChanges:       map[string]emailChange{},
+		passwordResets:     map[string]*passwordReset{},
+		loginChallenges:    map[int]*loginChallenge{},
+		oidcStates:         map[string]store.OIDCState{},
+		identities:         map[identityKey]int{},
+	}
+
+	return &store.Store{
+		Users:         &userStore{d},
+		Notes:         &noteStore{d},
+		Folders:       &folderStore{d},
+		Tags:          &tagStore{d},
+		Revisions:     &revisionStore{d},
+		Trash:         &trashStore{d},
+		Tokens:        &tokenStore{d},
+		Transfer:      &transferStore{d},
+		LoginAttempts: ratelimit.NewMemoryStore(),
+	}
+}
+
+// data berisi semua "tabel". Setiap method repository mengambil mu selama
+// operasinya sehingga setiap operasi berjalan seperti satu transaksi.
+type data struct {
+	mu  sync.Mutex
+	seq map[string]int // auto increment per tabel
+
+	users     map[int]*user
+	notes     map[int]*models.Note
+	folders   map[int]*models.Folder
+	tags      map[int]*models.Tag
+	noteTags  map[noteTag]bool
+	revisions map[int][]revision // per note ID, urut dari revisi terlama
+
+	refreshTokens      map[string]*store.RefreshToken // per token hash
+	revokedTokens      map[string]time.Time           // jti -> expires_at
+	accessTokens       map[int]*accessToken
+	emailVerifications map[string]expiringToken
+	emailChanges       map[string]emailChange
+	passwordResets     map[string]*passwordReset
+	loginChallenges    map[int]*loginChallenge
+	oidcStates         map[string]store.OIDCState
+	identities         map[identityKey]int
+}
+
+type user struct {
+	models.User
+	totpSecret       string
+	totpLastStep     *int64
+	tokensValidAfter *time.Time
+	retention        int
+	recoveryCodes    map[string]bool // hash -> sudah dipakai
+}
+
+type noteTag struct {
+	noteID, tagID int
+}
+
+type revision struct {
+	models.NoteRevision
+	userID int
+}
+
+type accessToken struct {
+	models.PersonalAccessToken
+	userID    int
+	tokenHash string
+}
+
+type expiringToken struct {
+	userID    int
+	expiresAt time.Time
+}
+
+type emailChange struct {
+	expiringToken
+	newEmail string
+}
+
+type passwordReset struct {
+	expiringToken
+	used bool
+}
+
+type loginChallenge struct {
+	store.LoginChallenge
+	tokenHash string
+}
+
+type identityKey struct {
+	issuer, subject string
+}
+
+// nextID mengembalikan ID berikutnya untuk tabel
+func (d *data) nextID(table string) int {
+	d.seq[table]++
+	return d.seq[table]
+}
+
+// now meniru kolom TIMESTAMP MySQL yang presisinya per detik
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// activeNote mengambil catatan aktif milik user, nil jika tidak ada atau ada di trash
+func (d *data) activeNote(userID, noteID int) *models.Note {
+	note, ok := d.notes[noteID]
+	if !ok || note.UserID != userID || note.DeletedAt != nil {
+		return nil
+	}
+	return note
+}
+
+// activeFolder mengambil folder aktif milik user, nil jika tidak ada atau ada di trash
+func (d *data) activeFolder(userID, folderID int) *models.Folder {
+	folder, ok := d.folders[folderID]
+	if !ok || folder.UserID != userID || folder.DeletedAt != nil {
+		return nil
+	}
+	return folder
+}
+
+// userTag mengambil tag milik user, nil jika tidak ada
+func (d *data) userTag(userID, tagID int) *models.Tag {
+	tag, ok := d.tags[tagID]
+	if !ok || tag.UserID != userID {
+		return nil
+	}
+	return tag
+}
+
+// folderChildren memetakan setiap folder aktif milik user ke daftar ID sub-folder langsungnya
+func (d *data) folderChildren(userID int) map[int][]int {
+	children := map[int][]int{}
+	for _, folder := range d.folders {
+		if folder.UserID == userID && folder.DeletedAt == nil {
+			if _, ok := children[folder.ID]; !ok {
+				children[folder.ID] = nil
+			}
+		}
+	}
+	for _, id := range sortedKeys(d.folders) {
+		folder := d.folders[id]
+		if _, ok := children[id]; ok && folder.ParentID != nil {
+			if _, ok := children[*folder.ParentID]; ok {
+				children[*folder.ParentID] = append(children[*folder.ParentID], id)
+			}
+		}
+	}
+	return children
+}
+
+// deleteNote menghapus permanen catatan beserta relasi tag dan revisinya (seperti ON DELETE CASCADE)
+func (d *data) deleteNote(noteID int) {
+	delete(d.notes, noteID)
+	delete(d.revisions, noteID)
+	for nt := range d.noteTags {
+		if nt.noteID == noteID {
+			delete(d.noteTags, nt)
+		}
+	}
+}
+
+// deleteFolder menghapus permanen folder; catatan dan sub-folder-nya kehilangan referensi
+// ke folder ini (seperti ON DELETE SET NULL)
+func (d *data) deleteFolder(folderID int) {
+	delete(d.folders, folderID)
+	for _, note := range d.notes {
+		if note.FolderID != nil && *note.FolderID == folderID {
+			note.FolderID = nil
+		}
+	}
+	for _, folder := range d.folders {
+		if folder.ParentID != nil && *folder.ParentID == folderID {
+			folder.ParentID = nil
+		}
+	}
+}
+
+// bumpVersion menaikkan version catatan (dipakai saat tag-nya berubah)
+func (d *data) bumpVersion(noteID int) {
+	if note, ok := d.notes[noteID]; ok {
+		note.Version++
+	}
+}
+
+// sortedKeys mengembalikan key map berurutan supaya hasil iterasi stabil
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func copyInt(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	i := *v
+	return &i
+}
+
+func copyTime(v *time.Time) *time.Time {
+	if v == nil {
+		return nil
+	}
+	t := *v
+	return &t
+}
+
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
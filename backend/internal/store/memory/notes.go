@@ -0,0 +1,397 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type noteStore struct {
+	*data
+}
+
+func (s *noteStore) List(ctx context.Context, userID int, filter store.NoteFilter, page store.Page) ([]models.Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folderIDs := map[int]bool{}
+	for _, id := range filter.FolderIDs {
+		folderIDs[id] = true
+	}
+	search := strings.ToLower(filter.Search)
+
+	notes := []models.Note{}
+	for _, id := range sortedKeys(s.notes) {
+		note := s.activeNote(userID, id)
+		if note == nil {
+			continue
+		}
+		if len(folderIDs) > 0 && (note.FolderID == nil || !folderIDs[*note.FolderID]) {
+			continue
+		}
+		if filter.TagID != 0 && !s.noteTags[noteTag{id, filter.TagID}] {
+			continue
+		}
+		if filter.Favorite && !note.IsFavorite {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(note.Title), search) && !strings.Contains(strings.ToLower(note.Content), search) {
+			continue
+		}
+		notes = append(notes, s.listNote(note))
+	}
+
+	return applyPage(notes, page), nil
+}
+
+func (s *noteStore) Get(ctx context.Context, userID, noteID int) (models.Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note := s.activeNote(userID, noteID)
+	if note == nil {
+		return models.Note{}, store.ErrNotFound
+	}
+	return copyNote(note), nil
+}
+
+func (s *noteStore) Create(ctx context.Context, note *models.Note) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if note.FolderID != nil && s.activeFolder(note.UserID, *note.FolderID) == nil {
+		return store.ErrFolderNotFound
+	}
+
+	created := now()
+	note.ID = s.nextID("notes")
+	note.Version = 1
+	note.CreatedAt = created
+	note.UpdatedAt = created
+
+	saved := copyNote(note)
+	saved.FolderName = ""
+	saved.Tags = nil
+	s.notes[note.ID] = &saved
+	return nil
+}
+
+func (s *noteStore) Update(ctx context.Context, userID, noteID int, fn func(note *models.Note) error) (models.Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.activeNote(userID, noteID)
+	if current == nil {
+		return models.Note{}, store.ErrNotFound
+	}
+
+	note := copyNote(current)
+	if err := fn(&note); err != nil {
+		return models.Note{}, err
+	}
+
+	if note.FolderID != nil && !sameID(current.FolderID, note.FolderID) && s.activeFolder(userID, *note.FolderID) == nil {
+		return models.Note{}, store.ErrFolderNotFound
+	}
+
+	// Revisi hanya dibuat jika judul atau isi berubah (bukan sekadar pindah folder/favorit)
+	if current.Title != note.Title || current.Content != note.Content {
+		s.saveRevision(noteID, userID, current.Title, current.Content)
+	}
+
+	current.FolderID = copyInt(note.FolderID)
+	current.Title = note.Title
+	current.Content = note.Content
+	current.IsFavorite = note.IsFavorite
+	current.Version++
+	current.UpdatedAt = now()
+
+	return copyNote(current), nil
+}
+
+func (s *noteStore) Delete(ctx context.Context, userID, noteID int, fn func(current models.Note) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note := s.activeNote(userID, noteID)
+	if note == nil {
+		return store.ErrNotFound
+	}
+
+	if fn != nil {
+		if err := fn(copyNote(note)); err != nil {
+			return err
+		}
+	}
+
+	deletedAt := now()
+	note.DeletedAt = &deletedAt
+	note.Version++
+	return nil
+}
+
+func (s *noteStore) Bulk(ctx context.Context, userID int, req models.BulkNoteRequest) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Validasi folder dan tag tujuan sebelum mengubah apa pun
+	if req.Action == "move" && req.FolderID != nil && s.activeFolder(userID, *req.FolderID) == nil {
+		return nil, store.ErrFolderNotFound
+	}
+
+	tagIDs := store.UniqueIDs(req.TagIDs)
+	for _, tagID := range tagIDs {
+		if s.userTag(userID, tagID) == nil {
+			return nil, store.ErrTagNotFound
+		}
+	}
+
+	var owned []int
+	for _, id := range store.UniqueIDs(req.NoteIDs) {
+		if s.activeNote(userID, id) != nil {
+			owned = append(owned, id)
+		}
+	}
+
+	switch req.Action {
+	case "move", "favorite", "unfavorite", "delete", "add_tags", "remove_tags":
+	default:
+		return nil, fmt.Errorf("action bulk %q tidak dikenal", req.Action)
+	}
+
+	deletedAt := now()
+	for _, id := range owned {
+		note := s.notes[id]
+		switch req.Action {
+		case "move":
+			note.FolderID = copyInt(req.FolderID)
+		case "favorite", "unfavorite":
+			note.IsFavorite = req.Action == "favorite"
+		case "delete":
+			note.DeletedAt = &deletedAt
+		case "add_tags":
+			for _, tagID := range tagIDs {
+				s.noteTags[noteTag{id, tagID}] = true
+			}
+		case "remove_tags":
+			for _, tagID := range tagIDs {
+				delete(s.noteTags, noteTag{id, tagID})
+			}
+		}
+		note.Version++
+	}
+
+	return owned, nil
+}
+
+func (s *noteStore) Search(ctx context.Context, userID int, q store.SearchQuery) ([]models.SearchResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := []models.SearchResult{}
+	for _, id := range sortedKeys(s.notes) {
+		note := s.activeNote(userID, id)
+		if note == nil {
+			continue
+		}
+		if q.FolderID != 0 && (note.FolderID == nil || *note.FolderID != q.FolderID) {
+			continue
+		}
+		if q.TagID != 0 && !s.noteTags[noteTag{id, q.TagID}] {
+			continue
+		}
+		if q.Favorite && !note.IsFavorite {
+			continue
+		}
+
+		score, ok := matchTerms(q.Terms, note.Title, note.Content)
+		if !ok {
+			continue
+		}
+		results = append(results, models.SearchResult{Note: s.listNote(note), Score: score})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].UpdatedAt.After(results[j].UpdatedAt)
+	})
+
+	if q.Offset >= len(results) {
+		return []models.SearchResult{}, nil
+	}
+	results = results[q.Offset:]
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+// listNote menyalin catatan beserta nama folder-nya seperti hasil query list
+func (s *noteStore) listNote(note *models.Note) models.Note {
+	result := copyNote(note)
+	if note.FolderID != nil {
+		if folder, ok := s.folders[*note.FolderID]; ok && folder.UserID == note.UserID {
+			result.FolderName = folder.Name
+		}
+	}
+	return result
+}
+
+// saveRevision menyimpan title/content sebagai revisi baru lalu membuang revisi
+// yang melebihi retention user
+func (d *data) saveRevision(noteID, userID int, title, content string) {
+	revisions := d.revisions[noteID]
+
+	next := 1
+	if len(revisions) > 0 {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+
+	revisions = append(revisions, revision{
+		NoteRevision: models.NoteRevision{
+			ID:        d.nextID("note_revisions"),
+			NoteID:    noteID,
+			Revision:  next,
+			Title:     title,
+			Content:   content,
+			CreatedAt: now(),
+		},
+		userID: userID,
+	})
+
+	retention := defaultRevisionRetention
+	if u, ok := d.users[userID]; ok {
+		retention = u.retention
+	}
+	d.revisions[noteID] = pruneRevisions(revisions, next-retention)
+}
+
+// pruneRevisions membuang revisi dengan nomor <= maxRevision
+func pruneRevisions(revisions []revision, maxRevision int) []revision {
+	kept := revisions[:0]
+	for _, rev := range revisions {
+		if rev.Revision > maxRevision {
+			kept = append(kept, rev)
+		}
+	}
+	return kept
+}
+
+// applyPage mengurutkan catatan, menerapkan cursor lalu memotong sesuai limit
+func applyPage(notes []models.Note, p store.Page) []models.Note {
+	compare := func(a, b models.Note) int {
+		var c int
+		switch p.Sort {
+		case store.SortTitle:
+			c = strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case store.SortUpdatedAt:
+			c = compareTime(a.UpdatedAt, b.UpdatedAt)
+		default:
+			c = compareTime(a.CreatedAt, b.CreatedAt)
+		}
+		if c == 0 {
+			c = a.ID - b.ID
+		}
+		if p.Desc {
+			c = -c
+		}
+		return c
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return compare(notes[i], notes[j]) < 0
+	})
+
+	if p.After != nil {
+		after := models.Note{ID: p.After.ID, Title: p.After.Title, CreatedAt: p.After.Time, UpdatedAt: p.After.Time}
+		i := sort.Search(len(notes), func(i int) bool {
+			return compare(notes[i], after) > 0
+		})
+		notes = notes[i:]
+	}
+
+	if p.Limit > 0 && len(notes) > p.Limit {
+		notes = notes[:p.Limit]
+	}
+	return notes
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// matchTerms mencocokkan term pencarian ke judul dan isi catatan. Semua term positif harus
+// cocok dan tidak ada term pengecualian yang cocok. Skor adalah jumlah kecocokan term positif,
+// kecocokan di judul dihitung dua kali.
+func matchTerms(terms []store.SearchTerm, title, content string) (float64, bool) {
+	titleWords := words(title)
+	contentWords := words(content)
+	lowerTitle := strings.ToLower(title)
+	lowerContent := strings.ToLower(content)
+
+	var score float64
+	hasPositive := false
+	for _, t := range terms {
+		text := strings.ToLower(t.Text)
+
+		var hits int
+		if t.Phrase {
+			hits = 2*strings.Count(lowerTitle, text) + strings.Count(lowerContent, text)
+		} else {
+			hits = 2*countWords(titleWords, text, t.Prefix) + countWords(contentWords, text, t.Prefix)
+		}
+
+		if t.Exclude {
+			if hits > 0 {
+				return 0, false
+			}
+			continue
+		}
+
+		if hits == 0 {
+			return 0, false
+		}
+		hasPositive = true
+		score += float64(hits)
+	}
+
+	return score, hasPositive
+}
+
+// words memecah teks menjadi kata huruf kecil
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
+
+func countWords(words []string, term string, prefix bool) int {
+	count := 0
+	for _, w := range words {
+		if w == term || (prefix && strings.HasPrefix(w, term)) {
+			count++
+		}
+	}
+	return count
+}
+
+func copyNote(note *models.Note) models.Note {
+	result := *note
+	result.FolderID = copyInt(note.FolderID)
+	result.DeletedAt = copyTime(note.DeletedAt)
+	result.Tags = nil
+	return result
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+)
+
+type revisionStore struct {
+	*data
+}
+
+func (s *revisionStore) List(ctx context.Context, userID, noteID int) ([]models.NoteRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.activeNote(userID, noteID) == nil {
+		return nil, store.ErrNotFound
+	}
+
+	revisions := []models.NoteRevision{}
+	all := s.revisions[noteID]
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].userID == userID {
+			revisions = append(revisions, all[i].NoteRevision)
+		}
+	}
+	return revisions, nil
+}
+
+func (s *revisionStore) Get(ctx context.Context, userID, noteID, revision int) (models.NoteRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.revision(userID, noteID, revision)
+}
+
+func (s *revisionStore) Restore(ctx context.Context, userID, noteID, revision int) (models.NoteRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note := s.activeNote(userID, noteID)
+	if note == nil {
+		return models.NoteRevision{}, store.ErrNotFound
+	}
+
+	rev, err := s.revision(userID, noteID, revision)
+	if err != nil {
+		return models.NoteRevision{}, store.ErrRevisionNotFound
+	}
+
+	// Isi saat ini disimpan dulu sebagai revisi baru supaya restore juga bisa di-undo
+	s.saveRevision(noteID, userID, note.Title, note.Content)
+
+	note.Title = rev.Title
+	note.Content = rev.Content
+	note.Version++
+	note.UpdatedAt = now()
+	return rev, nil
+}
+
+func (s *revisionStore) Retention(ctx context.Context, userID int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return 0, store.ErrNotFound
+	}
+	return u.retention, nil
+}
+
+func (s *revisionStore) SetRetention(ctx context.Context, userID, retention int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok {
+		u.retention = retention
+	}
+
+	for noteID, revisions := range s.revisions {
+		if len(revisions) == 0 || revisions[0].userID != userID {
+			continue
+		}
+		latest := revisions[len(revisions)-1].Revision
+		s.revisions[noteID] = pruneRevisions(revisions, latest-retention)
+	}
+	return nil
+}
+
+// revision mengambil satu revisi milik user, ErrNotFound jika tidak ada
+func (s *revisionStore) revision(userID, noteID, number int) (models.NoteRevision, error) {
+	for _, rev := range s.revisions[noteID] {
+		if rev.Revision == number && rev.userID == userID {
+			return rev.NoteRevision, nil
+		}
+	}
+	return models.NoteRevision{}, store.ErrNotFound
+}
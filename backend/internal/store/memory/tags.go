@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"sort"
+	"strings"
+)
+
+type tagStore struct {
+	*data
+}
+
+func (s *tagStore) List(ctx context.Context, userID int) ([]models.Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tags := []models.Tag{}
+	for _, id := range sortedKeys(s.tags) {
+		tag := s.tags[id]
+		if tag.UserID != userID {
+			continue
+		}
+
+		result := *tag
+		for nt := range s.noteTags {
+			if nt.tagID == id && s.activeNote(userID, nt.noteID) != nil {
+				result.NoteCount++
+			}
+		}
+		tags = append(tags, result)
+	}
+
+	sortTags(tags)
+	return tags, nil
+}
+
+func (s *tagStore) Get(ctx context.Context, userID, tagID int) (models.Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag := s.userTag(userID, tagID)
+	if tag == nil {
+		return models.Tag{}, store.ErrNotFound
+	}
+	return *tag, nil
+}
+
+func (s *tagStore) Create(ctx context.Context, tag *models.Tag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tagNameTaken(tag.UserID, tag.Name, 0) {
+		return store.ErrDuplicate
+	}
+
+	tag.ID = s.nextID("tags")
+	tag.CreatedAt = now()
+
+	saved := *tag
+	saved.NoteCount = 0
+	s.tags[tag.ID] = &saved
+	return nil
+}
+
+func (s *tagStore) Rename(ctx context.Context, userID, tagID int, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag := s.userTag(userID, tagID)
+	if tag == nil {
+		return store.ErrNotFound
+	}
+	if s.tagNameTaken(userID, name, tagID) {
+		return store.ErrDuplicate
+	}
+
+	tag.Name = name
+
+	// Nama tag tampil di catatan, jadi version catatan yang memakai tag ini ikut dinaikkan
+	for nt := range s.noteTags {
+		if nt.tagID == tagID {
+			s.bumpVersion(nt.noteID)
+		}
+	}
+	return nil
+}
+
+func (s *tagStore) Merge(ctx context.Context, userID, sourceID, targetID int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.userTag(userID, sourceID) == nil {
+		return 0, store.ErrNotFound
+	}
+	if s.userTag(userID, targetID) == nil {
+		return 0, store.ErrTagNotFound
+	}
+
+	var moved int64
+	for nt := range s.noteTags {
+		if nt.tagID != sourceID {
+			continue
+		}
+		s.bumpVersion(nt.noteID)
+		moved++
+
+		// Catatan yang sudah punya kedua tag cukup kehilangan tag sumber
+		s.noteTags[noteTag{nt.noteID, targetID}] = true
+		delete(s.noteTags, nt)
+	}
+
+	delete(s.tags, sourceID)
+	return moved, nil
+}
+
+func (s *tagStore) Delete(ctx context.Context, userID, tagID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.userTag(userID, tagID) == nil {
+		return store.ErrNotFound
+	}
+
+	delete(s.tags, tagID)
+	for nt := range s.noteTags {
+		if nt.tagID == tagID {
+			delete(s.noteTags, nt)
+		}
+	}
+	return nil
+}
+
+func (s *tagStore) Attach(ctx context.Context, userID, noteID, tagID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.activeNote(userID, noteID) == nil {
+		return store.ErrNotFound
+	}
+	if s.userTag(userID, tagID) == nil {
+		return store.ErrTagNotFound
+	}
+
+	nt := noteTag{noteID, tagID}
+	if s.noteTags[nt] {
+		return store.ErrDuplicate
+	}
+
+	s.noteTags[nt] = true
+	s.bumpVersion(noteID)
+	return nil
+}
+
+func (s *tagStore) Detach(ctx context.Context, userID, noteID, tagID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.activeNote(userID, noteID) == nil {
+		return store.ErrNotFound
+	}
+
+	nt := noteTag{noteID, tagID}
+	if !s.noteTags[nt] {
+		return store.ErrTagNotFound
+	}
+
+	delete(s.noteTags, nt)
+	s.bumpVersion(noteID)
+	return nil
+}
+
+func (s *tagStore) ForNotes(ctx context.Context, userID int, noteIDs []int) (map[int][]models.Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := map[int][]models.Tag{}
+	for _, noteID := range store.UniqueIDs(noteIDs) {
+		for _, tagID := range sortedKeys(s.tags) {
+			tag := s.tags[tagID]
+			if tag.UserID == userID && s.noteTags[noteTag{noteID, tagID}] {
+				result[noteID] = append(result[noteID], *tag)
+			}
+		}
+		if tags, ok := result[noteID]; ok {
+			sortTags(tags)
+		}
+	}
+	return result, nil
+}
+
+// tagNameTaken mengecek apakah user sudah punya tag lain (selain exceptID) dengan nama ini.
+// Perbandingan tidak membedakan huruf besar/kecil, seperti collation MySQL.
+func (d *data) tagNameTaken(userID int, name string, exceptID int) bool {
+	for _, tag := range d.tags {
+		if tag.UserID == userID && tag.ID != exceptID && strings.EqualFold(tag.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortTags mengurutkan tag berdasarkan nama, seperti ORDER BY name ASC
+func sortTags(tags []models.Tag) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i].Name) < strings.ToLower(tags[j].Name)
+	})
+}
@@ -0,0 +1,292 @@
+package memory
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"sort"
+	"time"
+)
+
+type tokenStore struct {
+	*data
+}
+
+func (s *tokenStore) CreateRefreshToken(ctx context.Context, token store.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createRefreshToken(token)
+}
+
+func (s *tokenStore) createRefreshToken(token store.RefreshToken) error {
+	if _, ok := s.refreshTokens[token.TokenHash]; ok {
+		return store.ErrDuplicate
+	}
+
+	token.ID = s.nextID("refresh_tokens")
+	token.RotatedAt = nil
+	token.RevokedAt = nil
+	s.refreshTokens[token.TokenHash] = &token
+	return nil
+}
+
+func (s *tokenStore) RotateRefreshToken(ctx context.Context, tokenHash string, successor store.RefreshToken) (store.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.refreshTokens[tokenHash]
+	if !ok {
+		return store.RefreshToken{}, store.ErrNotFound
+	}
+
+	prior := *token
+	prior.RotatedAt = copyTime(token.RotatedAt)
+	prior.RevokedAt = copyTime(token.RevokedAt)
+
+	t := time.Now().UTC()
+	switch {
+	case token.RevokedAt != nil, t.After(token.ExpiresAt):
+	case token.RotatedAt != nil:
+		// Token lama dipakai lagi: kemungkinan dicuri, cabut semua token di family ini
+		for _, other := range s.refreshTokens {
+			if other.FamilyID == token.FamilyID && other.RevokedAt == nil {
+				revokedAt := t
+				other.RevokedAt = &revokedAt
+			}
+		}
+	default:
+		successor.UserID = token.UserID
+		successor.FamilyID = token.FamilyID
+		if err := s.createRefreshToken(successor); err != nil {
+			return store.RefreshToken{}, err
+		}
+		token.RotatedAt = &t
+	}
+
+	return prior, nil
+}
+
+func (s *tokenStore) RevokeRefreshFamily(ctx context.Context, userID int, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.refreshTokens[tokenHash]
+	if !ok || token.UserID != userID {
+		return nil
+	}
+
+	t := time.Now().UTC()
+	for _, other := range s.refreshTokens {
+		if other.UserID == userID && other.FamilyID == token.FamilyID && other.RevokedAt == nil {
+			revokedAt := t
+			other.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (s *tokenStore) RevokeAccessToken(ctx context.Context, jti string, userID int, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.revokedTokens[jti]; !ok {
+		s.revokedTokens[jti] = expiresAt
+	}
+
+	// Token yang sudah kedaluwarsa tidak perlu disimpan lagi
+	t := time.Now()
+	for id, exp := range s.revokedTokens {
+		if exp.Before(t) {
+			delete(s.revokedTokens, id)
+		}
+	}
+	return nil
+}
+
+func (s *tokenStore) AccessTokenStatus(ctx context.Context, jti string, userID int) (bool, *time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return false, nil, store.ErrNotFound
+	}
+	_, revoked := s.revokedTokens[jti]
+	return revoked, copyTime(u.tokensValidAfter), nil
+}
+
+func (s *tokenStore) RevokeAllForUser(ctx context.Context, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := time.Now().UTC().Truncate(time.Second)
+	if u, ok := s.users[userID]; ok {
+		validAfter := t
+		u.tokensValidAfter = &validAfter
+	}
+
+	for _, token := range s.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			revokedAt := t
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (s *tokenStore) ListAccessTokens(ctx context.Context, userID int) ([]models.PersonalAccessToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := []models.PersonalAccessToken{}
+	for _, id := range sortedKeys(s.accessTokens) {
+		if token := s.accessTokens[id]; token.userID == userID {
+			tokens = append(tokens, copyAccessToken(token.PersonalAccessToken))
+		}
+	}
+
+	// Terbaru dulu, seperti ORDER BY created_at DESC
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].ID > tokens[j].ID
+	})
+	return tokens, nil
+}
+
+func (s *tokenStore) CreateAccessToken(ctx context.Context, userID int, token *models.PersonalAccessToken, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token.ID = s.nextID("personal_access_tokens")
+	token.CreatedAt = now()
+	token.LastUsedAt = nil
+
+	s.accessTokens[token.ID] = &accessToken{
+		PersonalAccessToken: copyAccessToken(*token),
+		userID:              userID,
+		tokenHash:           tokenHash,
+	}
+	return nil
+}
+
+func (s *tokenStore) UseAccessToken(ctx context.Context, tokenHash string) (int, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := now()
+	for _, token := range s.accessTokens {
+		if token.tokenHash != tokenHash {
+			continue
+		}
+		if token.ExpiresAt != nil && t.After(*token.ExpiresAt) {
+			break
+		}
+		if u, ok := s.users[token.userID]; !ok || u.DisabledAt != nil {
+			break
+		}
+
+		token.LastUsedAt = &t
+		return token.userID, append([]string(nil), token.Scopes...), nil
+	}
+	return 0, nil, store.ErrNotFound
+}
+
+func (s *tokenStore) DeleteAccessToken(ctx context.Context, userID, tokenID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.accessTokens[tokenID]
+	if !ok || token.userID != userID {
+		return store.ErrNotFound
+	}
+	delete(s.accessTokens, tokenID)
+	return nil
+}
+
+func (s *tokenStore) CreateLoginChallenge(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Challenge lama yang sudah kedaluwarsa tidak perlu disimpan lagi
+	t := time.Now()
+	for id, c := range s.loginChallenges {
+		if c.UserID == userID && c.ExpiresAt.Before(t) {
+			delete(s.loginChallenges, id)
+		}
+	}
+
+	id := s.nextID("login_challenges")
+	s.loginChallenges[id] = &loginChallenge{
+		LoginChallenge: store.LoginChallenge{ID: id, UserID: userID, ExpiresAt: expiresAt},
+		tokenHash:      tokenHash,
+	}
+	return nil
+}
+
+func (s *tokenStore) LoginChallenge(ctx context.Context, tokenHash string) (store.LoginChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.loginChallenges {
+		if c.tokenHash == tokenHash {
+			return c.LoginChallenge, nil
+		}
+	}
+	return store.LoginChallenge{}, store.ErrNotFound
+}
+
+func (s *tokenStore) AttemptLoginChallenge(ctx context.Context, challengeID, maxAttempts int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.loginChallenges[challengeID]
+	if !ok || c.Attempts >= maxAttempts {
+		return store.ErrNotFound
+	}
+	c.Attempts++
+	return nil
+}
+
+func (s *tokenStore) ConsumeLoginChallenge(ctx context.Context, challengeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.loginChallenges[challengeID]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.loginChallenges, challengeID)
+	return nil
+}
+
+func (s *tokenStore) CreateOIDCState(ctx context.Context, state store.OIDCState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := time.Now()
+	for hash, st := range s.oidcStates {
+		if st.ExpiresAt.Before(t) {
+			delete(s.oidcStates, hash)
+		}
+	}
+
+	s.oidcStates[state.StateHash] = state
+	return nil
+}
+
+func (s *tokenStore) ConsumeOIDCState(ctx context.Context, stateHash string) (store.OIDCState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.oidcStates[stateHash]
+	if !ok {
+		return store.OIDCState{}, store.ErrNotFound
+	}
+	delete(s.oidcStates, stateHash)
+	return state, nil
+}
+
+func copyAccessToken(token models.PersonalAccessToken) models.PersonalAccessToken {
+	token.Scopes = append([]string(nil), token.Scopes...)
+	token.ExpiresAt = copyTime(token.ExpiresAt)
+	token.LastUsedAt = copyTime(token.LastUsedAt)
+	return token
+}
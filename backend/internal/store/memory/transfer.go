@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"sort"
+	"time"
+)
+
+type transferStore struct {
+	*data
+}
+
+func (s *transferStore) Export(ctx context.Context, userID int) (models.UserExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return models.UserExport{}, store.ErrNotFound
+	}
+
+	data := models.UserExport{
+		Version:    models.UserExportVersion,
+		ExportedAt: time.Now().UTC(),
+		User: models.ExportUser{
+			Username:          u.Username,
+			Email:             u.Email,
+			FullName:          u.FullName,
+			PasswordHash:      u.PasswordHash,
+			CreatedAt:         u.CreatedAt,
+			EmailVerifiedAt:   copyTime(u.EmailVerifiedAt),
+			RevisionRetention: u.retention,
+		},
+		Folders: []models.ExportFolder{},
+		Tags:    []models.ExportTag{},
+		Notes:   []models.ExportNote{},
+	}
+
+	for _, id := range sortedKeys(s.folders) {
+		if f := s.folders[id]; f.UserID == userID {
+			data.Folders = append(data.Folders, models.ExportFolder{
+				ID: f.ID, ParentID: copyInt(f.ParentID), Name: f.Name, CreatedAt: f.CreatedAt, DeletedAt: copyTime(f.DeletedAt),
+			})
+		}
+	}
+
+	for _, id := range sortedKeys(s.tags) {
+		if t := s.tags[id]; t.UserID == userID {
+			data.Tags = append(data.Tags, models.ExportTag{ID: t.ID, Name: t.Name, CreatedAt: t.CreatedAt})
+		}
+	}
+
+	for _, id := range sortedKeys(s.notes) {
+		n := s.notes[id]
+		if n.UserID != userID {
+			continue
+		}
+
+		note := models.ExportNote{
+			ID: n.ID, FolderID: copyInt(n.FolderID), Title: n.Title, Content: n.Content, IsFavorite: n.IsFavorite,
+			Version: n.Version, CreatedAt: n.CreatedAt, UpdatedAt: n.UpdatedAt, DeletedAt: copyTime(n.DeletedAt),
+			TagIDs: []int{}, Revisions: []models.ExportRevision{},
+		}
+		for _, tagID := range sortedKeys(s.tags) {
+			if s.noteTags[noteTag{n.ID, tagID}] {
+				note.TagIDs = append(note.TagIDs, tagID)
+			}
+		}
+		for _, rev := range s.revisions[n.ID] {
+			note.Revisions = append(note.Revisions, models.ExportRevision{Revision: rev.Revision, Title: rev.Title, Content: rev.Content, CreatedAt: rev.CreatedAt})
+		}
+		data.Notes = append(data.Notes, note)
+	}
+
+	return data, nil
+}
+
+func (s *transferStore) Import(ctx context.Context, data models.UserExport) (int, error) {
+	if err := store.CheckExport(data); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usernameTaken(data.User.Username, 0) || s.emailTaken(data.User.Email, 0) {
+		return 0, store.ErrDuplicate
+	}
+
+	userID := s.nextID("users")
+	s.users[userID] = &user{
+		User: models.User{
+			ID:              userID,
+			Username:        data.User.Username,
+			Email:           data.User.Email,
+			PasswordHash:    data.User.PasswordHash,
+			FullName:        data.User.FullName,
+			CreatedAt:       data.User.CreatedAt,
+			EmailVerifiedAt: copyTime(data.User.EmailVerifiedAt),
+		},
+		retention:     data.User.RevisionRetention,
+		recoveryCodes: map[string]bool{},
+	}
+
+	// ID di file dipetakan ke ID baru; parent diisi setelah semua folder punya ID
+	folderIDs := map[int]int{}
+	for _, f := range data.Folders {
+		folderIDs[f.ID] = s.nextID("folders")
+	}
+	for _, f := range data.Folders {
+		var parentID *int
+		if f.ParentID != nil {
+			id := folderIDs[*f.ParentID]
+			parentID = &id
+		}
+		id := folderIDs[f.ID]
+		s.folders[id] = &models.Folder{ID: id, UserID: userID, ParentID: parentID, Name: f.Name, CreatedAt: f.CreatedAt, DeletedAt: copyTime(f.DeletedAt)}
+	}
+
+	tagIDs := map[int]int{}
+	for _, t := range data.Tags {
+		id := s.nextID("tags")
+		tagIDs[t.ID] = id
+		s.tags[id] = &models.Tag{ID: id, UserID: userID, Name: t.Name, CreatedAt: t.CreatedAt}
+	}
+
+	for _, n := range data.Notes {
+		var folderID *int
+		if n.FolderID != nil {
+			id := folderIDs[*n.FolderID]
+			folderID = &id
+		}
+
+		noteID := s.nextID("notes")
+		s.notes[noteID] = &models.Note{
+			ID: noteID, UserID: userID, FolderID: folderID, Title: n.Title, Content: n.Content, IsFavorite: n.IsFavorite,
+			Version: n.Version, CreatedAt: n.CreatedAt, UpdatedAt: n.UpdatedAt, DeletedAt: copyTime(n.DeletedAt),
+		}
+		for _, tagID := range n.TagIDs {
+			s.noteTags[noteTag{noteID, tagIDs[tagID]}] = true
+		}
+
+		// Revisi disimpan urut dari yang terlama, sama seperti saveRevision
+		revisions := append([]models.ExportRevision(nil), n.Revisions...)
+		sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+		for _, rev := range revisions {
+			s.revisions[noteID] = append(s.revisions[noteID], revision{
+				NoteRevision: models.NoteRevision{
+					ID: s.nextID("note_revisions"), NoteID: noteID, Revision: rev.Revision, Title: rev.Title, Content: rev.Content, CreatedAt: rev.CreatedAt,
+				},
+				userID: userID,
+			})
+		}
+	}
+
+	return userID, nil
+}
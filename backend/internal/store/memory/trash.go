@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"sort"
+	"time"
+)
+
+type trashStore struct {
+	*data
+}
+
+func (s *trashStore) List(ctx context.Context, userID int) ([]models.Note, []models.Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notes := []models.Note{}
+	for _, id := range sortedKeys(s.notes) {
+		if note := s.notes[id]; note.UserID == userID && note.DeletedAt != nil {
+			notes = append(notes, copyNote(note))
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].DeletedAt.After(*notes[j].DeletedAt)
+	})
+
+	folders := []models.Folder{}
+	for _, id := range sortedKeys(s.folders) {
+		if folder := s.folders[id]; folder.UserID == userID && folder.DeletedAt != nil {
+			folders = append(folders, copyFolder(folder))
+		}
+	}
+	sort.SliceStable(folders, func(i, j int) bool {
+		return folders[i].DeletedAt.After(*folders[j].DeletedAt)
+	})
+
+	return notes, folders, nil
+}
+
+func (s *trashStore) RestoreNote(ctx context.Context, userID, noteID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note, ok := s.notes[noteID]
+	if !ok || note.UserID != userID || note.DeletedAt == nil {
+		return store.ErrNotFound
+	}
+
+	// Folder-nya masih di trash: catatan dipindah ke luar folder supaya tetap terlihat
+	if note.FolderID != nil && s.activeFolder(userID, *note.FolderID) == nil {
+		note.FolderID = nil
+	}
+	note.DeletedAt = nil
+	return nil
+}
+
+func (s *trashStore) RestoreFolder(ctx context.Context, userID, folderID int) (int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folder, ok := s.folders[folderID]
+	if !ok || folder.UserID != userID || folder.DeletedAt == nil {
+		return 0, 0, store.ErrNotFound
+	}
+	deletedAt := *folder.DeletedAt
+
+	// Parent-nya masih di trash: folder dipindah ke root
+	if folder.ParentID != nil && s.activeFolder(userID, *folder.ParentID) == nil {
+		folder.ParentID = nil
+	}
+
+	// Sub-folder yang dihapus bersamaan (mode cascade) punya deleted_at yang sama persis
+	children := map[int][]int{}
+	for _, id := range sortedKeys(s.folders) {
+		f := s.folders[id]
+		if f.UserID == userID && f.DeletedAt != nil && f.DeletedAt.Equal(deletedAt) && f.ParentID != nil {
+			children[*f.ParentID] = append(children[*f.ParentID], id)
+		}
+	}
+
+	ids := store.SubtreeIDs(children, folderID)
+	inSubtree := map[int]bool{}
+	for _, id := range ids {
+		inSubtree[id] = true
+		s.folders[id].DeletedAt = nil
+	}
+
+	// Catatan yang dihapus bersamaan dengan folder punya deleted_at yang sama persis
+	restoredNotes := 0
+	for _, note := range s.notes {
+		if note.UserID == userID && note.DeletedAt != nil && note.DeletedAt.Equal(deletedAt) && note.FolderID != nil && inSubtree[*note.FolderID] {
+			note.DeletedAt = nil
+			restoredNotes++
+		}
+	}
+
+	return len(ids), restoredNotes, nil
+}
+
+func (s *trashStore) DeleteNote(ctx context.Context, userID, noteID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note, ok := s.notes[noteID]
+	if !ok || note.UserID != userID || note.DeletedAt == nil {
+		return store.ErrNotFound
+	}
+	s.deleteNote(noteID)
+	return nil
+}
+
+func (s *trashStore) DeleteFolder(ctx context.Context, userID, folderID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folder, ok := s.folders[folderID]
+	if !ok || folder.UserID != userID || folder.DeletedAt == nil {
+		return store.ErrNotFound
+	}
+	s.deleteFolder(folderID)
+	return nil
+}
+
+func (s *trashStore) Empty(ctx context.Context, userID int) (int64, error) {
+	return s.purge(func(u int, _ time.Time) bool { return u == userID }), nil
+}
+
+func (s *trashStore) PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.purge(func(_ int, deletedAt time.Time) bool { return deletedAt.Before(cutoff) }), nil
+}
+
+// purge menghapus permanen item trash yang cocok dengan match(userID, deletedAt)
+func (s *trashStore) purge(match func(userID int, deletedAt time.Time) bool) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for _, id := range sortedKeys(s.notes) {
+		if note := s.notes[id]; note.DeletedAt != nil && match(note.UserID, *note.DeletedAt) {
+			s.deleteNote(id)
+			deleted++
+		}
+	}
+	for _, id := range sortedKeys(s.folders) {
+		if folder, ok := s.folders[id]; ok && folder.DeletedAt != nil && match(folder.UserID, *folder.DeletedAt) {
+			s.deleteFolder(id)
+			deleted++
+		}
+	}
+	return deleted
+}
@@ -0,0 +1,373 @@
+package memory
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"strings"
+	"time"
+)
+
+type userStore struct {
+	*data
+}
+
+func (s *userStore) Create(ctx context.Context, u *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usernameTaken(u.Username, 0) || s.emailTaken(u.Email, 0) {
+		return store.ErrDuplicate
+	}
+
+	u.ID = s.nextID("users")
+	u.CreatedAt = now()
+	u.TwoFactorEnabled = false
+	u.DisabledAt = nil
+
+	saved := *u
+	saved.EmailVerifiedAt = copyTime(u.EmailVerifiedAt)
+	s.users[u.ID] = &user{User: saved, retention: defaultRevisionRetention, recoveryCodes: map[string]bool{}}
+	return nil
+}
+
+func (s *userStore) Get(ctx context.Context, userID int) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return models.User{}, store.ErrNotFound
+	}
+	return u.model(), nil
+}
+
+func (s *userStore) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if strings.EqualFold(u.Email, email) {
+			return u.model(), nil
+		}
+	}
+	return models.User{}, store.ErrNotFound
+}
+
+func (s *userStore) List(ctx context.Context) ([]models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := []models.User{}
+	for _, id := range sortedKeys(s.users) {
+		users = append(users, s.users[id].model())
+	}
+	return users, nil
+}
+
+func (s *userStore) UsernameTaken(ctx context.Context, username string, exceptID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.usernameTaken(username, exceptID), nil
+}
+
+func (s *userStore) EmailTaken(ctx context.Context, email string, exceptID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.emailTaken(email, exceptID), nil
+}
+
+func (s *userStore) UpdateProfile(ctx context.Context, userID int, username, fullName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return nil
+	}
+	if s.usernameTaken(username, userID) {
+		return store.ErrDuplicate
+	}
+
+	u.Username = username
+	u.FullName = fullName
+	return nil
+}
+
+func (s *userStore) SetPassword(ctx context.Context, userID int, passwordHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok {
+		u.PasswordHash = passwordHash
+	}
+	return nil
+}
+
+func (s *userStore) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if !disabled {
+		u.DisabledAt = nil
+	} else if u.DisabledAt == nil {
+		disabledAt := now()
+		u.DisabledAt = &disabledAt
+	}
+	return nil
+}
+
+func (s *userStore) CreateEmailVerification(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.emailVerifications[tokenHash] = expiringToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (s *userStore) VerifyEmail(ctx context.Context, tokenHash string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.emailVerifications[tokenHash]
+	if !ok || time.Now().After(token.expiresAt) {
+		return 0, store.ErrNotFound
+	}
+
+	if u, ok := s.users[token.userID]; ok && u.EmailVerifiedAt == nil {
+		verifiedAt := now()
+		u.EmailVerifiedAt = &verifiedAt
+	}
+
+	// Semua link verifikasi milik user ini tidak berlaku lagi
+	for hash, t := range s.emailVerifications {
+		if t.userID == token.userID {
+			delete(s.emailVerifications, hash)
+		}
+	}
+	return token.userID, nil
+}
+
+func (s *userStore) RequestEmailChange(ctx context.Context, userID int, newEmail, tokenHash string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Hanya permintaan terakhir yang berlaku
+	for hash, c := range s.emailChanges {
+		if c.userID == userID {
+			delete(s.emailChanges, hash)
+		}
+	}
+
+	s.emailChanges[tokenHash] = emailChange{expiringToken{userID, expiresAt}, newEmail}
+	return nil
+}
+
+func (s *userStore) ConfirmEmailChange(ctx context.Context, tokenHash string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change, ok := s.emailChanges[tokenHash]
+	if !ok {
+		return "", store.ErrNotFound
+	}
+
+	if time.Now().After(change.expiresAt) {
+		delete(s.emailChanges, tokenHash)
+		return "", store.ErrExpired
+	}
+	if s.emailTaken(change.newEmail, change.userID) {
+		return "", store.ErrDuplicate
+	}
+
+	delete(s.emailChanges, tokenHash)
+	if u, ok := s.users[change.userID]; ok {
+		verifiedAt := now()
+		u.Email = change.newEmail
+		u.EmailVerifiedAt = &verifiedAt
+	}
+	return change.newEmail, nil
+}
+
+func (s *userStore) CreatePasswordReset(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.passwordResets[tokenHash] = &passwordReset{expiringToken: expiringToken{userID, expiresAt}}
+	return nil
+}
+
+func (s *userStore) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reset, ok := s.passwordResets[tokenHash]
+	if !ok || reset.used || time.Now().After(reset.expiresAt) {
+		return 0, store.ErrNotFound
+	}
+
+	if u, ok := s.users[reset.userID]; ok {
+		u.PasswordHash = passwordHash
+	}
+
+	// Tandai token ini terpakai dan buang token reset lain milik user yang sama
+	reset.used = true
+	for hash, r := range s.passwordResets {
+		if r.userID == reset.userID && hash != tokenHash {
+			delete(s.passwordResets, hash)
+		}
+	}
+	return reset.userID, nil
+}
+
+func (s *userStore) TwoFactor(ctx context.Context, userID int) (store.TwoFactor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return store.TwoFactor{}, store.ErrNotFound
+	}
+	return store.TwoFactor{Secret: u.totpSecret, Enabled: u.TwoFactorEnabled}, nil
+}
+
+func (s *userStore) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok && !u.TwoFactorEnabled {
+		u.totpSecret = secret
+		u.totpLastStep = nil
+	}
+	return nil
+}
+
+func (s *userStore) EnableTwoFactor(ctx context.Context, userID int, secret string, step int64, recoveryHashes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok || u.TwoFactorEnabled || u.totpSecret != secret {
+		return store.ErrConflict
+	}
+
+	u.TwoFactorEnabled = true
+	u.totpLastStep = &step
+	u.replaceRecoveryCodes(recoveryHashes)
+	return nil
+}
+
+func (s *userStore) DisableTwoFactor(ctx context.Context, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok {
+		u.TwoFactorEnabled = false
+		u.totpSecret = ""
+		u.totpLastStep = nil
+		u.recoveryCodes = map[string]bool{}
+	}
+	return nil
+}
+
+func (s *userStore) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok || !u.TwoFactorEnabled || (u.totpLastStep != nil && step <= *u.totpLastStep) {
+		return false, nil
+	}
+	u.totpLastStep = &step
+	return true, nil
+}
+
+func (s *userStore) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return false, nil
+	}
+	used, exists := u.recoveryCodes[codeHash]
+	if !exists || used {
+		return false, nil
+	}
+	u.recoveryCodes[codeHash] = true
+	return true, nil
+}
+
+func (s *userStore) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok {
+		u.replaceRecoveryCodes(codeHashes)
+	}
+	return nil
+}
+
+func (s *userStore) FindIdentity(ctx context.Context, issuer, subject string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userID, ok := s.identities[identityKey{issuer, subject}]
+	if !ok {
+		return 0, store.ErrNotFound
+	}
+	return userID, nil
+}
+
+func (s *userStore) LinkIdentity(ctx context.Context, userID int, issuer, subject, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := identityKey{issuer, subject}
+	if _, ok := s.identities[key]; ok {
+		return store.ErrDuplicate
+	}
+	s.identities[key] = userID
+	return nil
+}
+
+// model menyalin data user beserta status 2FA-nya
+func (u *user) model() models.User {
+	result := u.User
+	result.EmailVerifiedAt = copyTime(u.EmailVerifiedAt)
+	result.DisabledAt = copyTime(u.DisabledAt)
+	return result
+}
+
+func (u *user) replaceRecoveryCodes(hashes []string) {
+	u.recoveryCodes = map[string]bool{}
+	for _, hash := range hashes {
+		u.recoveryCodes[hash] = false
+	}
+}
+
+// usernameTaken dan emailTaken mengecek nilai unik tanpa membedakan huruf besar/kecil,
+// seperti collation MySQL
+func (d *data) usernameTaken(username string, exceptID int) bool {
+	for _, u := range d.users {
+		if u.ID != exceptID && strings.EqualFold(u.Username, username) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *data) emailTaken(email string, exceptID int) bool {
+	for _, u := range d.users {
+		if u.ID != exceptID && strings.EqualFold(u.Email, email) {
+			return true
+		}
+	}
+	return false
+}
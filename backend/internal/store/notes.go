@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"time"
+)
+
+// Kolom yang bisa dipakai untuk mengurutkan daftar catatan
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortTitle     = "title"
+)
+
+// NoteStore menyimpan catatan milik user
+type NoteStore interface {
+	// List mengambil catatan aktif (bukan di trash) sesuai filter dan halaman, tanpa tag
+	List(ctx context.Context, userID int, filter NoteFilter, page Page) ([]models.Note, error)
+
+	// Get mengambil satu catatan aktif, ErrNotFound jika tidak ada atau ada di trash
+	Get(ctx context.Context, userID, noteID int) (models.Note, error)
+
+	// Create menyimpan catatan baru lalu mengisi ID, version dan waktu pembuatannya.
+	// ErrFolderNotFound jika FolderID bukan folder aktif milik user.
+	Create(ctx context.Context, note *models.Note) error
+
+	// Update mengunci catatan, memanggil fn untuk mengubahnya, lalu menyimpan hasilnya
+	// dengan version baru. Error dari fn membatalkan update dan dikembalikan apa adanya.
+	// Jika judul atau isi berubah, isi lama disimpan sebagai revisi.
+	Update(ctx context.Context, userID, noteID int, fn func(note *models.Note) error) (models.Note, error)
+
+	// Delete memindahkan catatan ke trash. fn (boleh nil) dipanggil dengan isi catatan
+	// saat ini; error dari fn membatalkan penghapusan.
+	Delete(ctx context.Context, userID, noteID int, fn func(current models.Note) error) error
+
+	// Bulk menjalankan satu aksi ke banyak catatan dalam satu transaksi dan mengembalikan
+	// ID catatan (tanpa duplikat) yang memang milik user dan ikut diproses.
+	// ErrFolderNotFound / ErrTagNotFound jika folder atau salah satu tag tujuan tidak ada.
+	Bulk(ctx context.Context, userID int, req models.BulkNoteRequest) ([]int, error)
+
+	// Search mencari catatan aktif berdasarkan term, diurutkan dari yang paling relevan
+	Search(ctx context.Context, userID int, q SearchQuery) ([]models.SearchResult, error)
+}
+
+// NoteFilter menyaring daftar catatan
+type NoteFilter struct {
+	FolderIDs []int  // kosong = semua folder
+	TagID     int    // 0 = tanpa filter tag
+	Favorite  bool   // true = hanya catatan favorit
+	Search    string // cocokkan sebagian judul atau isi, kosong = tanpa filter
+}
+
+// Page mengatur urutan dan jumlah catatan yang diambil (cursor pagination)
+type Page struct {
+	Limit int
+	Sort  string // SortCreatedAt, SortUpdatedAt atau SortTitle
+	Desc  bool
+	After *Cursor // posisi item terakhir dari halaman sebelumnya, nil = halaman pertama
+}
+
+// Cursor menandai posisi sebuah catatan dalam urutan Page.Sort
+type Cursor struct {
+	Time  time.Time // untuk sort created_at / updated_at
+	Title string    // untuk sort title
+	ID    int
+}
+
+// SearchQuery berisi query pencarian yang sudah dipecah menjadi term beserta filternya
+type SearchQuery struct {
+	Terms    []SearchTerm
+	FolderID int // 0 = semua folder
+	TagID    int // 0 = semua tag
+	Favorite bool
+	Limit    int
+	Offset   int
+}
+
+// SearchTerm adalah satu bagian dari query pencarian user
+type SearchTerm struct {
+	Text    string
+	Phrase  bool // "kata kata" - harus cocok persis berurutan
+	Prefix  bool // kata* - cocok dengan awalan
+	Exclude bool // -kata - catatan yang mengandung kata ini dibuang
+}
+
+// HasPositiveTerm mengecek apakah ada term yang bukan pengecualian. Pencarian tanpa term
+// positif tidak bisa dijalankan (MySQL menolak query boolean yang hanya berisi pengecualian).
+func HasPositiveTerm(terms []SearchTerm) bool {
+	for _, t := range terms {
+		if !t.Exclude {
+			return true
+		}
+	}
+	return false
+}
+
+// RevisionStore menyimpan riwayat revisi catatan dan pengaturan retention-nya
+type RevisionStore interface {
+	// List mengambil riwayat revisi sebuah catatan aktif, terbaru dulu
+	List(ctx context.Context, userID, noteID int) ([]models.NoteRevision, error)
+
+	// Get mengambil satu revisi, ErrNotFound jika tidak ada
+	Get(ctx context.Context, userID, noteID, revision int) (models.NoteRevision, error)
+
+	// Restore mengembalikan judul dan isi catatan ke revisi tertentu. Isi saat ini disimpan
+	// dulu sebagai revisi baru. ErrNotFound jika catatan tidak ada, ErrRevisionNotFound
+	// jika revisinya tidak ada.
+	Restore(ctx context.Context, userID, noteID, revision int) (models.NoteRevision, error)
+
+	// Retention mengambil jumlah revisi yang disimpan per catatan milik user
+	Retention(ctx context.Context, userID int) (int, error)
+
+	// SetRetention mengubah retention dan langsung membuang revisi yang melebihi batas baru
+	SetRetention(ctx context.Context, userID, retention int) error
+}
+
+// TrashStore mengelola catatan dan folder yang sudah dihapus (soft delete)
+type TrashStore interface {
+	// List mengambil semua catatan dan folder milik user yang ada di trash, terbaru dulu
+	List(ctx context.Context, userID int) ([]models.Note, []models.Folder, error)
+
+	// RestoreNote mengembalikan catatan dari trash. Jika folder-nya masih di trash,
+	// catatan dipindah ke luar folder.
+	RestoreNote(ctx context.Context, userID, noteID int) error
+
+	// RestoreFolder mengembalikan folder beserta sub-folder dan catatan yang terhapus
+	// bersamanya, lalu mengembalikan jumlah folder dan catatan yang di-restore
+	RestoreFolder(ctx context.Context, userID, folderID int) (int, int, error)
+
+	// DeleteNote dan DeleteFolder menghapus permanen satu item yang ada di trash
+	DeleteNote(ctx context.Context, userID, noteID int) error
+	DeleteFolder(ctx context.Context, userID, folderID int) error
+
+	// Empty menghapus permanen semua isi trash milik user
+	Empty(ctx context.Context, userID int) (int64, error)
+
+	// PurgeBefore menghapus permanen item trash semua user yang dihapus sebelum cutoff
+	PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,26 @@
+package sqlstore_test
+
+import (
+	"notes-api/internal/store/storetest"
+	"os"
+	"testing"
+)
+
+func TestContractSQLite(t *testing.T) {
+	storetest.RunContract(t, storetest.OpenSQLite)
+}
+
+// MySQL dan PostgreSQL hanya dijalankan jika DSN database test-nya diset
+func TestContractMySQL(t *testing.T) {
+	if os.Getenv(storetest.MySQLDSNEnv) == "" {
+		t.Skipf("%s tidak diset", storetest.MySQLDSNEnv)
+	}
+	storetest.RunContract(t, storetest.OpenMySQL)
+}
+
+func TestContractPostgres(t *testing.T) {
+	if os.Getenv(storetest.PostgresDSNEnv) == "" {
+		t.Skipf("%s tidak diset", storetest.PostgresDSNEnv)
+	}
+	storetest.RunContract(t, storetest.OpenPostgres)
+}
@@ -0,0 +1,90 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"notes-api/internal/store"
+)
+
+// dialect berisi perbedaan antar database. Semua query di package ini ditulis dengan
+// sintaks MySQL, lalu diterjemahkan oleh rebind sebelum dikirim ke driver.
+type dialect interface {
+	// rebind menerjemahkan query bersintaks MySQL ke sintaks database ini
+	rebind(query string) string
+
+	// arg menyesuaikan satu nilai argumen query sebelum dikirim ke driver
+	arg(v interface{}) interface{}
+
+	// insertID menjalankan INSERT (sudah di-rebind) lalu mengembalikan ID baris baru
+	insertID(ctx context.Context, q dbtx, query string, args []interface{}) (int, error)
+
+	// isDuplicate mengecek apakah err adalah pelanggaran unique key
+	isDuplicate(err error) bool
+
+	// textMatch membangun potongan query full-text search untuk term yang diberikan.
+	// Minimal harus ada satu term positif.
+	textMatch(terms []store.SearchTerm) textMatch
+}
+
+// textMatch adalah potongan query pencarian full-text untuk tabel notes (alias n)
+type textMatch struct {
+	join      string // JOIN tambahan, misalnya ke tabel index full-text
+	score     string // ekspresi skor relevansi, makin besar makin relevan
+	where     string // kondisi WHERE untuk catatan yang cocok
+	scoreArgs []interface{}
+	whereArgs []interface{}
+}
+
+// dialects memetakan nama driver database ke dialect-nya
+var dialects = map[string]dialect{
+	"mysql":    mysqlDialect{},
+	"sqlite":   sqliteDialect{},
+	"postgres": postgresDialect{},
+}
+
+// dbtx dipenuhi oleh *sql.DB dan *sql.Tx
+type dbtx interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// conn menjalankan query lewat *sql.DB atau *sql.Tx setelah diterjemahkan ke dialect database
+type conn struct {
+	q dbtx
+	d dialect
+}
+
+func (c conn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.q.QueryContext(ctx, c.d.rebind(query), c.args(args)...)
+}
+
+func (c conn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.q.QueryRowContext(ctx, c.d.rebind(query), c.args(args)...)
+}
+
+func (c conn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.q.ExecContext(ctx, c.d.rebind(query), c.args(args)...)
+}
+
+func (c conn) insertID(ctx context.Context, query string, args ...interface{}) (int, error) {
+	return c.d.insertID(ctx, c.q, c.d.rebind(query), c.args(args))
+}
+
+func (c conn) args(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, v := range args {
+		converted[i] = c.d.arg(v)
+	}
+	return converted
+}
+
+// lastInsertID dipakai dialect yang driver-nya mendukung sql.Result.LastInsertId
+func lastInsertID(ctx context.Context, q dbtx, query string, args []interface{}) (int, error) {
+	result, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
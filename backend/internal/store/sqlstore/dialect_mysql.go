@@ -0,0 +1,67 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+	"notes-api/internal/store"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Kode error MySQL untuk pelanggaran unique key
+const mysqlDuplicateEntry = 1062
+
+// mysqlDialect untuk driver go-sql-driver/mysql dengan parseTime=true. Query sudah
+// ditulis untuk MySQL, jadi tidak ada yang perlu diterjemahkan.
+type mysqlDialect struct{}
+
+func (mysqlDialect) rebind(query string) string {
+	return query
+}
+
+func (mysqlDialect) arg(v interface{}) interface{} {
+	return v
+}
+
+func (mysqlDialect) insertID(ctx context.Context, q dbtx, query string, args []interface{}) (int, error) {
+	return lastInsertID(ctx, q, query, args)
+}
+
+func (mysqlDialect) isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
+
+// textMatch memakai FULLTEXT index ft_notes_title_content dalam BOOLEAN MODE
+func (mysqlDialect) textMatch(terms []store.SearchTerm) textMatch {
+	against := buildBooleanQuery(terms)
+	match := "MATCH(n.title, n.content) AGAINST (? IN BOOLEAN MODE)"
+	return textMatch{
+		score:     match,
+		where:     match,
+		scoreArgs: []interface{}{against},
+		whereArgs: []interface{}{against},
+	}
+}
+
+// buildBooleanQuery mengubah daftar term menjadi ekspresi MATCH ... IN BOOLEAN MODE
+func buildBooleanQuery(terms []store.SearchTerm) string {
+	var parts []string
+	for _, t := range terms {
+		op := "+"
+		if t.Exclude {
+			op = "-"
+		}
+
+		switch {
+		case t.Phrase:
+			parts = append(parts, op+`"`+t.Text+`"`)
+		case t.Prefix:
+			parts = append(parts, op+t.Text+"*")
+		default:
+			parts = append(parts, op+t.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,118 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"notes-api/internal/store"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE Postgres untuk pelanggaran unique constraint
+const postgresUniqueViolation = "23505"
+
+// postgresSearchVector harus sama persis dengan ekspresi GIN index idx_notes_search di
+// migration supaya index dipakai. Config 'simple' tidak melakukan stemming, sama seperti
+// FULLTEXT MySQL.
+const postgresSearchVector = "to_tsvector('simple', coalesce(n.title::text, '') || ' ' || coalesce(n.content, ''))"
+
+// postgresDialect untuk driver pgx (database/sql). Kolom waktu bertipe TIMESTAMP tanpa
+// zona waktu dan selalu berisi UTC, sama seperti MySQL dengan parseTime=true.
+type postgresDialect struct{}
+
+// rebind mengganti placeholder ? menjadi $1, $2, ..., INSERT IGNORE menjadi
+// ON CONFLICT DO NOTHING, dan LIKE menjadi ILIKE (LIKE di MySQL tidak membedakan huruf
+// besar/kecil). Query di package ini tidak punya ? di dalam string literal.
+func (postgresDialect) rebind(query string) string {
+	if strings.Contains(query, "INSERT IGNORE INTO") {
+		query = strings.Replace(query, "INSERT IGNORE INTO", "INSERT INTO", 1) + " ON CONFLICT DO NOTHING"
+	}
+	query = strings.ReplaceAll(query, " LIKE ?", " ILIKE ?")
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// arg mengubah waktu ke UTC karena pgx menyimpan jam lokal apa adanya ke kolom TIMESTAMP
+func (postgresDialect) arg(v interface{}) interface{} {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC()
+	case *time.Time:
+		if t != nil {
+			return t.UTC()
+		}
+	case sql.NullTime:
+		if !t.Valid {
+			return nil
+		}
+		return t.Time.UTC()
+	}
+	return v
+}
+
+// insertID memakai RETURNING id karena pgx tidak mendukung sql.Result.LastInsertId
+func (postgresDialect) insertID(ctx context.Context, q dbtx, query string, args []interface{}) (int, error) {
+	var id int
+	err := q.QueryRowContext(ctx, query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (postgresDialect) isDuplicate(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == postgresUniqueViolation
+}
+
+// textMatch memakai tsvector dari judul dan isi catatan (GIN index idx_notes_search)
+func (postgresDialect) textMatch(terms []store.SearchTerm) textMatch {
+	query := buildTSQuery(terms)
+	return textMatch{
+		score:     "ts_rank(" + postgresSearchVector + ", to_tsquery('simple', ?))",
+		where:     postgresSearchVector + " @@ to_tsquery('simple', ?)",
+		scoreArgs: []interface{}{query},
+		whereArgs: []interface{}{query},
+	}
+}
+
+// buildTSQuery mengubah daftar term menjadi query to_tsquery. Setiap kata dikutip supaya
+// karakter di dalamnya tidak dibaca sebagai operator; kata dalam frasa digabung dengan
+// <-> (berurutan), term digabung dengan & dan pengecualian diberi !.
+func buildTSQuery(terms []store.SearchTerm) string {
+	var parts []string
+	for _, t := range terms {
+		var words []string
+		for _, word := range strings.Fields(t.Text) {
+			word = strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(word)
+			words = append(words, "'"+word+"'")
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		if t.Prefix {
+			words[len(words)-1] += ":*"
+		}
+		part := strings.Join(words, " <-> ")
+		if len(words) > 1 {
+			part = "(" + part + ")"
+		}
+		if t.Exclude {
+			part = "!" + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " & ")
+}
@@ -0,0 +1,94 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"notes-api/internal/store"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SQLiteTimeFormat adalah format kolom waktu di SQLite: selalu UTC dengan lebar tetap,
+// sama dengan default strftime('%Y-%m-%d %H:%M:%f') di migration, supaya perbandingan
+// string (WHERE, ORDER BY, cursor pagination) sama hasilnya dengan perbandingan waktu.
+const SQLiteTimeFormat = "2006-01-02 15:04:05.000"
+
+// sqliteDialect untuk driver modernc.org/sqlite. Koneksi dibuka dengan _txlock=immediate
+// sehingga setiap transaksi langsung mengunci database untuk write; SELECT ... FOR UPDATE
+// tidak diperlukan (dan tidak didukung SQLite).
+type sqliteDialect struct{}
+
+func (sqliteDialect) rebind(query string) string {
+	query = strings.TrimSuffix(query, " FOR UPDATE")
+	return strings.Replace(query, "INSERT IGNORE INTO", "INSERT OR IGNORE INTO", 1)
+}
+
+func (sqliteDialect) arg(v interface{}) interface{} {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC().Format(SQLiteTimeFormat)
+	case *time.Time:
+		if t != nil {
+			return t.UTC().Format(SQLiteTimeFormat)
+		}
+	case sql.NullTime:
+		if !t.Valid {
+			return nil
+		}
+		return t.Time.UTC().Format(SQLiteTimeFormat)
+	}
+	return v
+}
+
+func (sqliteDialect) insertID(ctx context.Context, q dbtx, query string, args []interface{}) (int, error) {
+	return lastInsertID(ctx, q, query, args)
+}
+
+func (sqliteDialect) isDuplicate(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// textMatch memakai tabel FTS5 notes_fts; bm25 bernilai negatif (makin kecil makin relevan)
+// sehingga dibalik menjadi skor
+func (sqliteDialect) textMatch(terms []store.SearchTerm) textMatch {
+	return textMatch{
+		join:      " INNER JOIN notes_fts ON notes_fts.rowid = n.id",
+		score:     "-bm25(notes_fts)",
+		where:     "notes_fts MATCH ?",
+		whereArgs: []interface{}{buildFTS5Query(terms)},
+	}
+}
+
+// buildFTS5Query mengubah daftar term menjadi query FTS5. Setiap term dikutip supaya
+// karakter di dalamnya tidak dibaca sebagai operator; term positif digabung dengan AND
+// (spasi) dan pengecualian ditambahkan dengan NOT.
+func buildFTS5Query(terms []store.SearchTerm) string {
+	var include, exclude []string
+	for _, t := range terms {
+		part := `"` + strings.ReplaceAll(t.Text, `"`, `""`) + `"`
+		if t.Prefix {
+			part += "*"
+		}
+
+		if t.Exclude {
+			exclude = append(exclude, part)
+		} else {
+			include = append(include, part)
+		}
+	}
+
+	query := strings.Join(include, " ")
+	for _, part := range exclude {
+		query += " NOT " + part
+	}
+	return query
+}
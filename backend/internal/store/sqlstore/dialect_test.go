@@ -0,0 +1,88 @@
+package sqlstore
+
+import (
+	"notes-api/internal/store"
+	"testing"
+)
+
+// Terjemahan query diuji tanpa server database; perilakunya di database sungguhan diuji
+// oleh TestContractMySQL dan TestContractPostgres
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        dialect
+		query    string
+		expected string
+	}{
+		{
+			"postgres placeholder",
+			postgresDialect{},
+			"SELECT id FROM notes WHERE user_id = ? AND id IN (?, ?) LIMIT ?",
+			"SELECT id FROM notes WHERE user_id = $1 AND id IN ($2, $3) LIMIT $4",
+		},
+		{
+			"postgres insert ignore",
+			postgresDialect{},
+			"INSERT IGNORE INTO note_tags (note_id, tag_id) VALUES (?, ?), (?, ?)",
+			"INSERT INTO note_tags (note_id, tag_id) VALUES ($1, $2), ($3, $4) ON CONFLICT DO NOTHING",
+		},
+		{
+			"postgres like",
+			postgresDialect{},
+			"SELECT id FROM notes n WHERE (n.title LIKE ? OR n.content LIKE ?)",
+			"SELECT id FROM notes n WHERE (n.title ILIKE $1 OR n.content ILIKE $2)",
+		},
+		{
+			"postgres for update",
+			postgresDialect{},
+			"SELECT version FROM notes WHERE id = ? FOR UPDATE",
+			"SELECT version FROM notes WHERE id = $1 FOR UPDATE",
+		},
+		{
+			"sqlite insert ignore",
+			sqliteDialect{},
+			"INSERT IGNORE INTO revoked_tokens (jti, user_id, expires_at) VALUES (?, ?, ?)",
+			"INSERT OR IGNORE INTO revoked_tokens (jti, user_id, expires_at) VALUES (?, ?, ?)",
+		},
+		{
+			"sqlite for update",
+			sqliteDialect{},
+			"SELECT version FROM notes WHERE id = ? FOR UPDATE",
+			"SELECT version FROM notes WHERE id = ?",
+		},
+		{
+			"mysql tidak berubah",
+			mysqlDialect{},
+			"INSERT IGNORE INTO note_tags (note_id, tag_id) VALUES (?, ?)",
+			"INSERT IGNORE INTO note_tags (note_id, tag_id) VALUES (?, ?)",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.d.rebind(tt.query); got != tt.expected {
+			t.Errorf("%s:\n got  %q\n want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestSearchQueries(t *testing.T) {
+	terms := []store.SearchTerm{
+		{Text: "santan"},
+		{Text: "daging sapi", Phrase: true},
+		{Text: "angg", Prefix: true},
+		{Text: "rendang", Exclude: true},
+	}
+
+	if got, want := buildTSQuery(terms), `'santan' & ('daging' <-> 'sapi') & 'angg':* & !'rendang'`; got != want {
+		t.Errorf("buildTSQuery = %q, seharusnya %q", got, want)
+	}
+	if got, want := buildTSQuery([]store.SearchTerm{{Text: `o'neil\`}}), `'o''neil\\'`; got != want {
+		t.Errorf("buildTSQuery dengan kutip = %q, seharusnya %q", got, want)
+	}
+	if got, want := buildFTS5Query(terms), `"santan" "daging sapi" "angg"* NOT "rendang"`; got != want {
+		t.Errorf("buildFTS5Query = %q, seharusnya %q", got, want)
+	}
+	if got, want := buildBooleanQuery(terms), `+santan +"daging sapi" +angg* -rendang`; got != want {
+		t.Errorf("buildBooleanQuery = %q, seharusnya %q", got, want)
+	}
+}
@@ -0,0 +1,177 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"time"
+)
+
+type folderStore struct {
+	*sqlStore
+}
+
+func (s *folderStore) List(ctx context.Context, userID int) ([]models.Folder, error) {
+	query := "SELECT id, user_id, parent_id, name, created_at FROM folders WHERE user_id = ? AND deleted_at IS NULL ORDER BY created_at DESC"
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := []models.Folder{}
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+
+	return folders, rows.Err()
+}
+
+func (s *folderStore) Children(ctx context.Context, userID int) (map[int][]int, error) {
+	return loadFolderChildren(ctx, s.db, userID)
+}
+
+func (s *folderStore) Create(ctx context.Context, folder *models.Folder) error {
+	if folder.ParentID != nil {
+		if err := checkFolder(ctx, s.db, folder.UserID, *folder.ParentID); err != nil {
+			return err
+		}
+	}
+
+	query := "INSERT INTO folders (user_id, parent_id, name) VALUES (?, ?, ?)"
+	id, err := s.db.insertID(ctx, query, folder.UserID, folder.ParentID, folder.Name)
+	if err != nil {
+		return err
+	}
+
+	folder.ID = id
+	return s.db.QueryRowContext(ctx, "SELECT created_at FROM folders WHERE id = ?", id).Scan(&folder.CreatedAt)
+}
+
+func (s *folderStore) Update(ctx context.Context, userID, folderID int, fn func(folder *models.Folder) error) (models.Folder, error) {
+	var folder models.Folder
+	err := s.withTx(ctx, func(tx queryer) error {
+		query := "SELECT id, user_id, parent_id, name, created_at FROM folders WHERE id = ? AND user_id = ? AND deleted_at IS NULL FOR UPDATE"
+		current, err := scanFolder(tx.QueryRowContext(ctx, query, folderID, userID))
+		if err != nil {
+			return notFound(err)
+		}
+
+		folder = current
+		if err := fn(&folder); err != nil {
+			return err
+		}
+
+		if !sameID(current.ParentID, folder.ParentID) {
+			children, err := loadFolderChildren(ctx, tx, userID)
+			if err != nil {
+				return err
+			}
+			if err := store.CheckMove(children, folderID, folder.ParentID); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE folders SET name = ?, parent_id = ? WHERE id = ? AND user_id = ?", folder.Name, folder.ParentID, folderID, userID)
+		return err
+	})
+
+	return folder, err
+}
+
+func (s *folderStore) Delete(ctx context.Context, userID, folderID int, cascade bool) (int, error) {
+	// deleted_at yang sama dipakai untuk folder dan catatannya supaya bisa di-restore bersamaan
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+
+	var ids []int
+	err := s.withTx(ctx, func(tx queryer) error {
+		var parentID sql.NullInt64
+		err := tx.QueryRowContext(ctx, "SELECT parent_id FROM folders WHERE id = ? AND user_id = ? AND deleted_at IS NULL FOR UPDATE", folderID, userID).Scan(&parentID)
+		if err != nil {
+			return notFound(err)
+		}
+
+		ids = []int{folderID}
+		if cascade {
+			children, err := loadFolderChildren(ctx, tx, userID)
+			if err != nil {
+				return err
+			}
+			ids = store.SubtreeIDs(children, folderID)
+		} else {
+			query := "UPDATE folders SET parent_id = ? WHERE parent_id = ? AND user_id = ? AND deleted_at IS NULL"
+			if _, err := tx.ExecContext(ctx, query, parentID, folderID, userID); err != nil {
+				return err
+			}
+		}
+
+		args := append([]interface{}{deletedAt, userID}, intArgs(ids)...)
+
+		query := "UPDATE folders SET deleted_at = ? WHERE user_id = ? AND deleted_at IS NULL AND id IN (" + placeholders(len(ids)) + ")"
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		query = "UPDATE notes SET deleted_at = ? WHERE user_id = ? AND deleted_at IS NULL AND folder_id IN (" + placeholders(len(ids)) + ")"
+		_, err = tx.ExecContext(ctx, query, args...)
+		return err
+	})
+
+	return len(ids), err
+}
+
+// loadFolderChildren memetakan setiap folder aktif milik user ke daftar ID sub-folder langsungnya
+func loadFolderChildren(ctx context.Context, q queryer, userID int) (map[int][]int, error) {
+	rows, err := q.QueryContext(ctx, "SELECT id, parent_id FROM folders WHERE user_id = ? AND deleted_at IS NULL", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	children := map[int][]int{}
+	var parents [][2]int
+	for rows.Next() {
+		var id int
+		var parentID sql.NullInt64
+		if err := rows.Scan(&id, &parentID); err != nil {
+			return nil, err
+		}
+		if _, ok := children[id]; !ok {
+			children[id] = nil
+		}
+		if parentID.Valid {
+			parents = append(parents, [2]int{int(parentID.Int64), id})
+		}
+	}
+
+	for _, p := range parents {
+		if _, ok := children[p[0]]; ok {
+			children[p[0]] = append(children[p[0]], p[1])
+		}
+	}
+
+	return children, rows.Err()
+}
+
+func scanFolder(row scanner) (models.Folder, error) {
+	var folder models.Folder
+	var parentID sql.NullInt64
+	if err := row.Scan(&folder.ID, &folder.UserID, &parentID, &folder.Name, &folder.CreatedAt); err != nil {
+		return models.Folder{}, err
+	}
+	folder.ParentID = nullInt(parentID)
+	return folder, nil
+}
+
+// sameID membandingkan dua ID opsional (nil = tidak ada)
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
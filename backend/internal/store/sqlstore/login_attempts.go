@@ -0,0 +1,94 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"notes-api/internal/ratelimit"
+	"sync"
+	"time"
+)
+
+// loginAttemptStore menyimpan ratelimit.State di tabel login_attempts supaya dibagi antar instance server
+type loginAttemptStore struct {
+	*sqlStore
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+// Get mengambil State untuk key (State kosong jika belum ada)
+func (s *loginAttemptStore) Get(key string) (ratelimit.State, error) {
+	var state ratelimit.State
+	var lastFailure, blockedUntil sql.NullTime
+	query := "SELECT failures, last_failure, blocked_until FROM login_attempts WHERE rl_key = ?"
+	err := s.db.QueryRowContext(context.Background(), query, key).Scan(&state.Failures, &lastFailure, &blockedUntil)
+	if err == sql.ErrNoRows {
+		return ratelimit.State{}, nil
+	}
+	if err != nil {
+		return ratelimit.State{}, err
+	}
+
+	state.LastFailure = lastFailure.Time
+	state.BlockedUntil = blockedUntil.Time
+	return state, nil
+}
+
+// Update mengubah State untuk key di dalam transaksi dengan row lock
+func (s *loginAttemptStore) Update(key string, fn func(state *ratelimit.State)) (ratelimit.State, error) {
+	s.cleanup()
+
+	ctx := context.Background()
+	var state ratelimit.State
+	err := s.withTx(ctx, func(tx queryer) error {
+		// Pastikan row ada supaya bisa di-lock dengan FOR UPDATE
+		if _, err := tx.ExecContext(ctx, "INSERT IGNORE INTO login_attempts (rl_key, failures) VALUES (?, 0)", key); err != nil {
+			return err
+		}
+
+		var lastFailure, blockedUntil sql.NullTime
+		query := "SELECT failures, last_failure, blocked_until FROM login_attempts WHERE rl_key = ? FOR UPDATE"
+		if err := tx.QueryRowContext(ctx, query, key).Scan(&state.Failures, &lastFailure, &blockedUntil); err != nil {
+			return err
+		}
+		state.LastFailure = lastFailure.Time
+		state.BlockedUntil = blockedUntil.Time
+
+		fn(&state)
+
+		query = "UPDATE login_attempts SET failures = ?, last_failure = ?, blocked_until = ? WHERE rl_key = ?"
+		_, err := tx.ExecContext(ctx, query, state.Failures, zeroNull(state.LastFailure), zeroNull(state.BlockedUntil), key)
+		return err
+	})
+	if err != nil {
+		return ratelimit.State{}, err
+	}
+
+	return state, nil
+}
+
+// Delete menghapus State untuk key
+func (s *loginAttemptStore) Delete(key string) error {
+	_, err := s.db.ExecContext(context.Background(), "DELETE FROM login_attempts WHERE rl_key = ?", key)
+	return err
+}
+
+// cleanup membuang row lama paling sering sekali per 10 menit per instance
+func (s *loginAttemptStore) cleanup() {
+	s.mu.Lock()
+	now := time.Now().UTC()
+	if now.Sub(s.lastCleanup) < 10*time.Minute {
+		s.mu.Unlock()
+		return
+	}
+	s.lastCleanup = now
+	s.mu.Unlock()
+
+	cutoff := now.Add(-24 * time.Hour)
+	s.db.ExecContext(context.Background(), "DELETE FROM login_attempts WHERE last_failure < ? AND (blocked_until IS NULL OR blocked_until < ?)", cutoff, now)
+}
+
+// zeroNull menyimpan waktu kosong sebagai NULL
+func zeroNull(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,403 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"strings"
+	"time"
+)
+
+// Kolom catatan untuk query list (dengan nama folder) dan query satu catatan
+const (
+	noteListColumns = "n.id, n.user_id, n.folder_id, f.name, n.title, n.content, n.is_favorite, n.created_at, n.updated_at, n.version"
+	noteListFrom    = " FROM notes n LEFT JOIN folders f ON n.folder_id = f.id AND f.user_id = n.user_id"
+	noteColumns     = "id, user_id, folder_id, title, content, is_favorite, created_at, updated_at, version"
+)
+
+// sortColumns memetakan store.Page.Sort ke kolom tabel notes
+var sortColumns = map[string]string{
+	store.SortCreatedAt: "n.created_at",
+	store.SortUpdatedAt: "n.updated_at",
+	store.SortTitle:     "n.title",
+}
+
+type noteStore struct {
+	*sqlStore
+}
+
+func (s *noteStore) List(ctx context.Context, userID int, filter store.NoteFilter, page store.Page) ([]models.Note, error) {
+	query := "SELECT " + noteListColumns + noteListFrom + " WHERE n.user_id = ? AND n.deleted_at IS NULL"
+	args := []interface{}{userID}
+
+	if len(filter.FolderIDs) > 0 {
+		query += " AND n.folder_id IN (" + placeholders(len(filter.FolderIDs)) + ")"
+		args = append(args, intArgs(filter.FolderIDs)...)
+	}
+
+	if filter.TagID != 0 {
+		query += " AND EXISTS (SELECT 1 FROM note_tags nt WHERE nt.note_id = n.id AND nt.tag_id = ?)"
+		args = append(args, filter.TagID)
+	}
+
+	if filter.Favorite {
+		query += " AND n.is_favorite = true"
+	}
+
+	if filter.Search != "" {
+		query += " AND (n.title LIKE ? OR n.content LIKE ?)"
+		searchPattern := "%" + filter.Search + "%"
+		args = append(args, searchPattern, searchPattern)
+	}
+
+	query, args = applyPage(query, args, page)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []models.Note{}
+	for rows.Next() {
+		note, err := scanListNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+func (s *noteStore) Get(ctx context.Context, userID, noteID int) (models.Note, error) {
+	return getNote(ctx, s.db, userID, noteID, false)
+}
+
+func (s *noteStore) Create(ctx context.Context, note *models.Note) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		if note.FolderID != nil {
+			if err := checkFolder(ctx, tx, note.UserID, *note.FolderID); err != nil {
+				return err
+			}
+		}
+
+		query := "INSERT INTO notes (user_id, folder_id, title, content, is_favorite) VALUES (?, ?, ?, ?, ?)"
+		id, err := tx.insertID(ctx, query, note.UserID, note.FolderID, note.Title, note.Content, note.IsFavorite)
+		if err != nil {
+			return err
+		}
+
+		created, err := getNote(ctx, tx, note.UserID, id, false)
+		if err != nil {
+			return err
+		}
+
+		note.ID = created.ID
+		note.Version = created.Version
+		note.CreatedAt = created.CreatedAt
+		note.UpdatedAt = created.UpdatedAt
+		return nil
+	})
+}
+
+func (s *noteStore) Update(ctx context.Context, userID, noteID int, fn func(note *models.Note) error) (models.Note, error) {
+	err := s.withTx(ctx, func(tx queryer) error {
+		current, err := getNote(ctx, tx, userID, noteID, true)
+		if err != nil {
+			return err
+		}
+
+		note := current
+		if err := fn(&note); err != nil {
+			return err
+		}
+
+		if note.FolderID != nil && !sameID(current.FolderID, note.FolderID) {
+			if err := checkFolder(ctx, tx, userID, *note.FolderID); err != nil {
+				return err
+			}
+		}
+
+		// Revisi hanya dibuat jika judul atau isi berubah (bukan sekadar pindah folder/favorit)
+		if current.Title != note.Title || current.Content != note.Content {
+			if err := saveRevision(ctx, tx, noteID, userID, current.Title, current.Content); err != nil {
+				return err
+			}
+		}
+
+		query := "UPDATE notes SET folder_id = ?, title = ?, content = ?, is_favorite = ?, version = version + 1 WHERE id = ? AND user_id = ?"
+		_, err = tx.ExecContext(ctx, query, note.FolderID, note.Title, note.Content, note.IsFavorite, noteID, userID)
+		return err
+	})
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	// Ambil ulang supaya updated_at dan version sesuai dengan yang tersimpan
+	return s.Get(ctx, userID, noteID)
+}
+
+func (s *noteStore) Delete(ctx context.Context, userID, noteID int, fn func(current models.Note) error) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		current, err := getNote(ctx, tx, userID, noteID, true)
+		if err != nil {
+			return err
+		}
+
+		if fn != nil {
+			if err := fn(current); err != nil {
+				return err
+			}
+		}
+
+		query := "UPDATE notes SET deleted_at = ?, version = version + 1 WHERE id = ? AND user_id = ?"
+		_, err = tx.ExecContext(ctx, query, time.Now().UTC().Truncate(time.Second), noteID, userID)
+		return err
+	})
+}
+
+func (s *noteStore) Bulk(ctx context.Context, userID int, req models.BulkNoteRequest) ([]int, error) {
+	var owned []int
+	err := s.withTx(ctx, func(tx queryer) error {
+		// Validasi folder dan tag tujuan sebelum mengubah apa pun
+		if req.Action == "move" && req.FolderID != nil {
+			if err := checkFolder(ctx, tx, userID, *req.FolderID); err != nil {
+				return err
+			}
+		}
+
+		tagIDs := store.UniqueIDs(req.TagIDs)
+		if len(tagIDs) > 0 {
+			var count int
+			query := "SELECT COUNT(*) FROM tags WHERE user_id = ? AND id IN (" + placeholders(len(tagIDs)) + ")"
+			if err := tx.QueryRowContext(ctx, query, append([]interface{}{userID}, intArgs(tagIDs)...)...).Scan(&count); err != nil {
+				return err
+			}
+			if count != len(tagIDs) {
+				return store.ErrTagNotFound
+			}
+		}
+
+		// Cek kepemilikan semua catatan sekaligus
+		noteIDs := store.UniqueIDs(req.NoteIDs)
+		query := "SELECT id FROM notes WHERE user_id = ? AND deleted_at IS NULL AND id IN (" + placeholders(len(noteIDs)) + ") FOR UPDATE"
+		rows, err := tx.QueryContext(ctx, query, append([]interface{}{userID}, intArgs(noteIDs)...)...)
+		if err != nil {
+			return err
+		}
+		found := map[int]bool{}
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			found[id] = true
+		}
+		rows.Close()
+
+		for _, id := range noteIDs {
+			if found[id] {
+				owned = append(owned, id)
+			}
+		}
+
+		if len(owned) == 0 {
+			return nil
+		}
+		return applyBulkAction(ctx, tx, userID, req.Action, req.FolderID, tagIDs, owned)
+	})
+
+	return owned, err
+}
+
+// applyBulkAction menjalankan aksi bulk untuk catatan yang sudah dipastikan milik user
+func applyBulkAction(ctx context.Context, tx queryer, userID int, action string, folderID *int, tagIDs, noteIDs []int) error {
+	in := placeholders(len(noteIDs))
+	ids := intArgs(noteIDs)
+
+	var query string
+	var args []interface{}
+
+	switch action {
+	case "move":
+		query = "UPDATE notes SET folder_id = ?, version = version + 1 WHERE user_id = ? AND id IN (" + in + ")"
+		args = append([]interface{}{folderID, userID}, ids...)
+	case "favorite", "unfavorite":
+		query = "UPDATE notes SET is_favorite = ?, version = version + 1 WHERE user_id = ? AND id IN (" + in + ")"
+		args = append([]interface{}{action == "favorite", userID}, ids...)
+	case "delete":
+		query = "UPDATE notes SET deleted_at = ?, version = version + 1 WHERE user_id = ? AND id IN (" + in + ")"
+		args = append([]interface{}{time.Now().UTC().Truncate(time.Second), userID}, ids...)
+	case "add_tags":
+		// Relasi yang sudah ada dilewati (IGNORE)
+		var values []string
+		for _, noteID := range noteIDs {
+			for _, tagID := range tagIDs {
+				values = append(values, "(?, ?)")
+				args = append(args, noteID, tagID)
+			}
+		}
+		query = "INSERT IGNORE INTO note_tags (note_id, tag_id) VALUES " + strings.Join(values, ", ")
+	case "remove_tags":
+		query = "DELETE FROM note_tags WHERE note_id IN (" + in + ") AND tag_id IN (" + placeholders(len(tagIDs)) + ")"
+		args = append(ids, intArgs(tagIDs)...)
+	default:
+		return fmt.Errorf("action bulk %q tidak dikenal", action)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	// Perubahan tag juga mengubah representasi catatan, jadi version ikut dinaikkan
+	if action == "add_tags" || action == "remove_tags" {
+		_, err := tx.ExecContext(ctx, "UPDATE notes SET version = version + 1 WHERE user_id = ? AND id IN ("+in+")", append([]interface{}{userID}, ids...)...)
+		return err
+	}
+	return nil
+}
+
+func (s *noteStore) Search(ctx context.Context, userID int, q store.SearchQuery) ([]models.SearchResult, error) {
+	// Tidak ada database yang bisa mencari hanya dengan pengecualian
+	if !store.HasPositiveTerm(q.Terms) {
+		return []models.SearchResult{}, nil
+	}
+	match := s.dialect.textMatch(q.Terms)
+
+	query := `
+		SELECT ` + noteListColumns + `, ` + match.score + ` AS score
+		` + noteListFrom + match.join + `
+		WHERE n.user_id = ? AND n.deleted_at IS NULL AND ` + match.where
+	args := append([]interface{}{}, match.scoreArgs...)
+	args = append(args, userID)
+	args = append(args, match.whereArgs...)
+
+	if q.FolderID != 0 {
+		query += " AND n.folder_id = ?"
+		args = append(args, q.FolderID)
+	}
+
+	if q.TagID != 0 {
+		query += " AND EXISTS (SELECT 1 FROM note_tags nt WHERE nt.note_id = n.id AND nt.tag_id = ?)"
+		args = append(args, q.TagID)
+	}
+
+	if q.Favorite {
+		query += " AND n.is_favorite = true"
+	}
+
+	query += " ORDER BY score DESC, n.updated_at DESC LIMIT ? OFFSET ?"
+	args = append(args, q.Limit, q.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var res models.SearchResult
+		var folderID sql.NullInt64
+		var folderName sql.NullString
+		err := rows.Scan(&res.ID, &res.UserID, &folderID, &folderName, &res.Title, &res.Content, &res.IsFavorite, &res.CreatedAt, &res.UpdatedAt, &res.Version, &res.Score)
+		if err != nil {
+			return nil, err
+		}
+		res.FolderID = nullInt(folderID)
+		res.FolderName = folderName.String
+		results = append(results, res)
+	}
+
+	return results, rows.Err()
+}
+
+// applyPage menambahkan kondisi cursor, ORDER BY dan LIMIT ke query notes.
+// Query harus memakai alias "n" untuk tabel notes dan belum memiliki ORDER BY.
+func applyPage(query string, args []interface{}, p store.Page) (string, []interface{}) {
+	col, ok := sortColumns[p.Sort]
+	if !ok {
+		col = sortColumns[store.SortCreatedAt]
+	}
+
+	op, dir := ">", "ASC"
+	if p.Desc {
+		op, dir = "<", "DESC"
+	}
+
+	if p.After != nil {
+		var value interface{} = p.After.Time
+		if p.Sort == store.SortTitle {
+			value = p.After.Title
+		}
+		query += fmt.Sprintf(" AND (%s %s ? OR (%s = ? AND n.id %s ?))", col, op, col, op)
+		args = append(args, value, value, p.After.ID)
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s %s, n.id %s LIMIT ?", col, dir, dir)
+	args = append(args, p.Limit)
+
+	return query, args
+}
+
+// getNote mengambil satu catatan aktif milik user. Jika forUpdate true,
+// baris catatan dikunci (SELECT ... FOR UPDATE) sampai transaksi selesai.
+func getNote(ctx context.Context, q queryer, userID, noteID int, forUpdate bool) (models.Note, error) {
+	query := "SELECT " + noteColumns + " FROM notes WHERE id = ? AND user_id = ? AND deleted_at IS NULL"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	var note models.Note
+	var folderID sql.NullInt64
+	err := q.QueryRowContext(ctx, query, noteID, userID).Scan(&note.ID, &note.UserID, &folderID, &note.Title, &note.Content, &note.IsFavorite, &note.CreatedAt, &note.UpdatedAt, &note.Version)
+	if err != nil {
+		return models.Note{}, notFound(err)
+	}
+
+	note.FolderID = nullInt(folderID)
+	return note, nil
+}
+
+// scanListNote membaca satu baris hasil query dengan noteListColumns
+func scanListNote(row scanner) (models.Note, error) {
+	var note models.Note
+	var folderID sql.NullInt64
+	var folderName sql.NullString
+	err := row.Scan(&note.ID, &note.UserID, &folderID, &folderName, &note.Title, &note.Content, &note.IsFavorite, &note.CreatedAt, &note.UpdatedAt, &note.Version)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	note.FolderID = nullInt(folderID)
+	note.FolderName = folderName.String
+	return note, nil
+}
+
+// checkFolder memastikan folder aktif dan milik user, ErrFolderNotFound jika tidak
+func checkFolder(ctx context.Context, q queryer, userID, folderID int) error {
+	ok, err := exists(ctx, q, "SELECT COUNT(*) FROM folders WHERE id = ? AND user_id = ? AND deleted_at IS NULL", folderID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return store.ErrFolderNotFound
+	}
+	return nil
+}
+
+// checkNote memastikan catatan aktif dan milik user, ErrNotFound jika tidak
+func checkNote(ctx context.Context, q queryer, userID, noteID int) error {
+	ok, err := exists(ctx, q, "SELECT COUNT(*) FROM notes WHERE id = ? AND user_id = ? AND deleted_at IS NULL", noteID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return store.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+)
+
+type revisionStore struct {
+	*sqlStore
+}
+
+func (s *revisionStore) List(ctx context.Context, userID, noteID int) ([]models.NoteRevision, error) {
+	if err := checkNote(ctx, s.db, userID, noteID); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT id, note_id, revision, title, content, created_at FROM note_revisions WHERE note_id = ? AND user_id = ? ORDER BY revision DESC"
+	rows, err := s.db.QueryContext(ctx, query, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.NoteRevision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
+
+func (s *revisionStore) Get(ctx context.Context, userID, noteID, revision int) (models.NoteRevision, error) {
+	return getRevision(ctx, s.db, userID, noteID, revision)
+}
+
+func (s *revisionStore) Restore(ctx context.Context, userID, noteID, revision int) (models.NoteRevision, error) {
+	var rev models.NoteRevision
+	err := s.withTx(ctx, func(tx queryer) error {
+		current, err := getNote(ctx, tx, userID, noteID, true)
+		if err != nil {
+			return err
+		}
+
+		rev, err = getRevision(ctx, tx, userID, noteID, revision)
+		if errors.Is(err, store.ErrNotFound) {
+			return store.ErrRevisionNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// Isi saat ini disimpan dulu sebagai revisi baru supaya restore juga bisa di-undo
+		if err := saveRevision(ctx, tx, noteID, userID, current.Title, current.Content); err != nil {
+			return err
+		}
+
+		query := "UPDATE notes SET title = ?, content = ?, version = version + 1 WHERE id = ? AND user_id = ?"
+		_, err = tx.ExecContext(ctx, query, rev.Title, rev.Content, noteID, userID)
+		return err
+	})
+
+	return rev, err
+}
+
+func (s *revisionStore) Retention(ctx context.Context, userID int) (int, error) {
+	var retention int
+	err := s.db.QueryRowContext(ctx, "SELECT revision_retention FROM users WHERE id = ?", userID).Scan(&retention)
+	return retention, notFound(err)
+}
+
+func (s *revisionStore) SetRetention(ctx context.Context, userID, retention int) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET revision_retention = ? WHERE id = ?", retention, userID); err != nil {
+			return err
+		}
+
+		// Subquery dibungkus derived table karena MySQL tidak mengizinkan DELETE
+		// membaca tabel yang sama secara langsung
+		prune := `
+			DELETE FROM note_revisions WHERE id IN (
+				SELECT id FROM (
+					SELECT r.id FROM note_revisions r
+					INNER JOIN (
+						SELECT note_id, MAX(revision) AS max_revision FROM note_revisions WHERE user_id = ? GROUP BY note_id
+					) latest ON r.note_id = latest.note_id
+					WHERE r.revision <= latest.max_revision - ?
+				) AS pruned
+			)
+		`
+		_, err := tx.ExecContext(ctx, prune, userID, retention)
+		return err
+	})
+}
+
+// saveRevision menyimpan title/content sebagai revisi baru lalu membuang revisi
+// yang melebihi retention user. Harus dipanggil di dalam transaksi.
+func saveRevision(ctx context.Context, tx queryer, noteID, userID int, title, content string) error {
+	var next int
+	err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(revision), 0) + 1 FROM note_revisions WHERE note_id = ?", noteID).Scan(&next)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO note_revisions (note_id, user_id, revision, title, content) VALUES (?, ?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, noteID, userID, next, title, content); err != nil {
+		return err
+	}
+
+	var retention int
+	if err := tx.QueryRowContext(ctx, "SELECT revision_retention FROM users WHERE id = ?", userID).Scan(&retention); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM note_revisions WHERE note_id = ? AND revision <= ?", noteID, next-retention)
+	return err
+}
+
+func getRevision(ctx context.Context, q queryer, userID, noteID, revision int) (models.NoteRevision, error) {
+	query := "SELECT id, note_id, revision, title, content, created_at FROM note_revisions WHERE note_id = ? AND user_id = ? AND revision = ?"
+	rev, err := scanRevision(q.QueryRowContext(ctx, query, noteID, userID, revision))
+	return rev, notFound(err)
+}
+
+func scanRevision(row scanner) (models.NoteRevision, error) {
+	var rev models.NoteRevision
+	err := row.Scan(&rev.ID, &rev.NoteID, &rev.Revision, &rev.Title, &rev.Content, &rev.CreatedAt)
+	return rev, err
+}
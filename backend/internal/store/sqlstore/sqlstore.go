@@ -0,0 +1,135 @@
+// Package sqlstore mengimplementasikan interface di package store di atas database/sql.
+// Query ditulis dengan sintaks MySQL; bagian yang berbeda di database lain (PostgreSQL,
+// SQLite) diterjemahkan oleh dialect, lihat dialect.go.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"notes-api/internal/store"
+	"strings"
+	"time"
+)
+
+// New membuat semua repository di atas satu koneksi database. driver adalah nama
+// database yang dipakai db: mysql, postgres atau sqlite.
+func New(db *sql.DB, driver string) (*store.Store, error) {
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("database %q tidak didukung", driver)
+	}
+
+	s := &sqlStore{pool: db, db: conn{db, d}, dialect: d}
+	return &store.Store{
+		Users:         &userStore{s},
+		Notes:         &noteStore{s},
+		Folders:       &folderStore{s},
+		Tags:          &tagStore{s},
+		Revisions:     &revisionStore{s},
+		Trash:         &trashStore{s},
+		Tokens:        &tokenStore{s},
+		Transfer:      &transferStore{s},
+		LoginAttempts: &loginAttemptStore{sqlStore: s},
+	}, nil
+}
+
+// sqlStore berisi koneksi dan helper yang dipakai bersama oleh semua repository
+type sqlStore struct {
+	pool    *sql.DB
+	db      conn // pool yang query-nya diterjemahkan ke dialect
+	dialect dialect
+}
+
+// queryer dipenuhi oleh conn, baik di dalam maupun di luar transaksi
+type queryer interface {
+	dbtx
+	insertID(ctx context.Context, query string, args ...interface{}) (int, error)
+}
+
+// scanner dipenuhi oleh *sql.Row dan *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// withTx menjalankan fn di dalam transaksi; commit jika fn tidak mengembalikan error
+func (s *sqlStore) withTx(ctx context.Context, fn func(tx queryer) error) error {
+	tx, err := s.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(conn{tx, s.dialect}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// execAffected menjalankan query lalu mengembalikan ErrNotFound jika tidak ada baris yang berubah
+func execAffected(ctx context.Context, q queryer, query string, args ...interface{}) error {
+	result, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// exists menjalankan query COUNT(*) dan mengembalikan true jika hasilnya lebih dari nol
+func exists(ctx context.Context, q queryer, query string, args ...interface{}) (bool, error) {
+	var count int
+	if err := q.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// notFound mengubah sql.ErrNoRows menjadi store.ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.ErrNotFound
+	}
+	return err
+}
+
+// duplicate mengubah error unique key menjadi store.ErrDuplicate
+func (s *sqlStore) duplicate(err error) error {
+	if s.dialect.isDuplicate(err) {
+		return store.ErrDuplicate
+	}
+	return err
+}
+
+// placeholders membuat "?, ?, ?" sebanyak n untuk klausa IN
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// intArgs mengubah daftar ID menjadi argumen query
+func intArgs(ids []int) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
+}
+
+func nullInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
+
+func nullTime(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	t := v.Time
+	return &t
+}
@@ -0,0 +1,188 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+)
+
+type tagStore struct {
+	*sqlStore
+}
+
+func (s *tagStore) List(ctx context.Context, userID int) ([]models.Tag, error) {
+	query := "SELECT t.id, t.user_id, t.name, t.created_at, COUNT(nt.note_id) as note_count FROM tags t LEFT JOIN note_tags nt ON t.id = nt.tag_id AND nt.note_id IN (SELECT id FROM notes WHERE user_id = ? AND deleted_at IS NULL) WHERE t.user_id = ? GROUP BY t.id ORDER BY t.name ASC"
+	rows, err := s.db.QueryContext(ctx, query, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt, &tag.NoteCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (s *tagStore) Get(ctx context.Context, userID, tagID int) (models.Tag, error) {
+	var tag models.Tag
+	query := "SELECT id, user_id, name, created_at FROM tags WHERE id = ? AND user_id = ?"
+	err := s.db.QueryRowContext(ctx, query, tagID, userID).Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt)
+	return tag, notFound(err)
+}
+
+func (s *tagStore) Create(ctx context.Context, tag *models.Tag) error {
+	id, err := s.db.insertID(ctx, "INSERT INTO tags (user_id, name) VALUES (?, ?)", tag.UserID, tag.Name)
+	if err != nil {
+		return s.duplicate(err)
+	}
+
+	tag.ID = id
+	return s.db.QueryRowContext(ctx, "SELECT created_at FROM tags WHERE id = ?", id).Scan(&tag.CreatedAt)
+}
+
+func (s *tagStore) Rename(ctx context.Context, userID, tagID int, name string) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		// Cek kepemilikan dulu karena RowsAffected bernilai 0 juga jika nama tidak berubah
+		if err := checkTag(ctx, tx, userID, tagID, store.ErrNotFound); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE tags SET name = ? WHERE id = ? AND user_id = ?", name, tagID, userID); err != nil {
+			return s.duplicate(err)
+		}
+
+		// Nama tag tampil di catatan, jadi version catatan yang memakai tag ini ikut dinaikkan
+		_, err := tx.ExecContext(ctx, "UPDATE notes SET version = version + 1 WHERE user_id = ? AND id IN (SELECT note_id FROM note_tags WHERE tag_id = ?)", userID, tagID)
+		return err
+	})
+}
+
+func (s *tagStore) Merge(ctx context.Context, userID, sourceID, targetID int) (int64, error) {
+	var moved int64
+	err := s.withTx(ctx, func(tx queryer) error {
+		if err := checkTag(ctx, tx, userID, sourceID, store.ErrNotFound); err != nil {
+			return err
+		}
+		if err := checkTag(ctx, tx, userID, targetID, store.ErrTagNotFound); err != nil {
+			return err
+		}
+
+		// Catatan yang tag-nya berubah perlu version baru supaya ETag ikut berubah. Jumlah
+		// barisnya sekaligus menjadi jumlah catatan yang dipindah dari tag sumber.
+		result, err := tx.ExecContext(ctx, "UPDATE notes SET version = version + 1 WHERE user_id = ? AND id IN (SELECT note_id FROM note_tags WHERE tag_id = ?)", userID, sourceID)
+		if err != nil {
+			return err
+		}
+		if moved, err = result.RowsAffected(); err != nil {
+			return err
+		}
+
+		// Catatan yang sudah punya kedua tag dilewati (IGNORE) supaya tidak melanggar primary key.
+		// tag_id diambil dari join ke tags, bukan placeholder di daftar SELECT yang tipenya
+		// tidak bisa ditebak Postgres.
+		if _, err := tx.ExecContext(ctx, "INSERT IGNORE INTO note_tags (note_id, tag_id) SELECT nt.note_id, t.id FROM note_tags nt INNER JOIN tags t ON t.id = ? WHERE nt.tag_id = ?", targetID, sourceID); err != nil {
+			return err
+		}
+
+		// Relasi note_tags milik tag sumber ikut terhapus lewat ON DELETE CASCADE
+		_, err = tx.ExecContext(ctx, "DELETE FROM tags WHERE id = ? AND user_id = ?", sourceID, userID)
+		return err
+	})
+
+	return moved, err
+}
+
+func (s *tagStore) Delete(ctx context.Context, userID, tagID int) error {
+	return execAffected(ctx, s.db, "DELETE FROM tags WHERE id = ? AND user_id = ?", tagID, userID)
+}
+
+func (s *tagStore) Attach(ctx context.Context, userID, noteID, tagID int) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		if err := checkNote(ctx, tx, userID, noteID); err != nil {
+			return err
+		}
+		if err := checkTag(ctx, tx, userID, tagID, store.ErrTagNotFound); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "INSERT INTO note_tags (note_id, tag_id) VALUES (?, ?)", noteID, tagID); err != nil {
+			return s.duplicate(err)
+		}
+
+		// Tag catatan berubah, naikkan version supaya ETag ikut berubah
+		_, err := tx.ExecContext(ctx, "UPDATE notes SET version = version + 1 WHERE id = ? AND user_id = ?", noteID, userID)
+		return err
+	})
+}
+
+func (s *tagStore) Detach(ctx context.Context, userID, noteID, tagID int) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		if err := checkNote(ctx, tx, userID, noteID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM note_tags WHERE note_id = ? AND tag_id = ?", noteID, tagID)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return store.ErrTagNotFound
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE notes SET version = version + 1 WHERE id = ? AND user_id = ?", noteID, userID)
+		return err
+	})
+}
+
+func (s *tagStore) ForNotes(ctx context.Context, userID int, noteIDs []int) (map[int][]models.Tag, error) {
+	result := map[int][]models.Tag{}
+	if len(noteIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT nt.note_id, t.id, t.user_id, t.name, t.created_at
+		FROM tags t
+		INNER JOIN note_tags nt ON t.id = nt.tag_id
+		WHERE nt.note_id IN (` + placeholders(len(noteIDs)) + `) AND t.user_id = ?
+		ORDER BY t.name ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, append(intArgs(noteIDs), userID)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var noteID int
+		var tag models.Tag
+		if err := rows.Scan(&noteID, &tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		result[noteID] = append(result[noteID], tag)
+	}
+
+	return result, rows.Err()
+}
+
+// checkTag memastikan tag milik user (baris tag dikunci sampai transaksi selesai),
+// mengembalikan errMissing jika tidak
+func checkTag(ctx context.Context, q queryer, userID, tagID int, errMissing error) error {
+	// Tanpa COUNT(*): Postgres tidak mengizinkan FOR UPDATE pada query agregat
+	var id int
+	err := q.QueryRowContext(ctx, "SELECT id FROM tags WHERE id = ? AND user_id = ? FOR UPDATE", tagID, userID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errMissing
+	}
+	return err
+}
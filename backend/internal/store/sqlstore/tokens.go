@@ -0,0 +1,218 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"strings"
+	"time"
+)
+
+type tokenStore struct {
+	*sqlStore
+}
+
+func (s *tokenStore) CreateRefreshToken(ctx context.Context, token store.RefreshToken) error {
+	return createRefreshToken(ctx, s.db, token)
+}
+
+func (s *tokenStore) RotateRefreshToken(ctx context.Context, tokenHash string, successor store.RefreshToken) (store.RefreshToken, error) {
+	var token store.RefreshToken
+	err := s.withTx(ctx, func(tx queryer) error {
+		var rotatedAt, revokedAt sql.NullTime
+		query := "SELECT id, user_id, family_id, token_hash, expires_at, rotated_at, revoked_at FROM refresh_tokens WHERE token_hash = ? FOR UPDATE"
+		err := tx.QueryRowContext(ctx, query, tokenHash).Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &rotatedAt, &revokedAt)
+		if err != nil {
+			return notFound(err)
+		}
+		token.RotatedAt = nullTime(rotatedAt)
+		token.RevokedAt = nullTime(revokedAt)
+
+		now := time.Now().UTC()
+		switch {
+		case token.RevokedAt != nil, now.After(token.ExpiresAt):
+			return nil
+		case token.RotatedAt != nil:
+			// Token lama dipakai lagi: kemungkinan dicuri, cabut semua token di family ini
+			_, err := tx.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL", now, token.FamilyID)
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE refresh_tokens SET rotated_at = ? WHERE id = ?", now, token.ID); err != nil {
+			return err
+		}
+
+		// Successor ikut di transaksi ini supaya token lama tidak pernah tercatat sudah
+		// dipakai tanpa ada token pengganti
+		successor.UserID = token.UserID
+		successor.FamilyID = token.FamilyID
+		return createRefreshToken(ctx, tx, successor)
+	})
+
+	return token, err
+}
+
+func createRefreshToken(ctx context.Context, q queryer, token store.RefreshToken) error {
+	query := "INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at) VALUES (?, ?, ?, ?)"
+	_, err := q.ExecContext(ctx, query, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt)
+	return err
+}
+
+func (s *tokenStore) RevokeRefreshFamily(ctx context.Context, userID int, tokenHash string) error {
+	query := `
+		UPDATE refresh_tokens SET revoked_at = ?
+		WHERE user_id = ? AND revoked_at IS NULL AND family_id = (
+			SELECT family_id FROM (SELECT family_id FROM refresh_tokens WHERE token_hash = ? AND user_id = ?) AS t
+		)
+	`
+	_, err := s.db.ExecContext(ctx, query, time.Now().UTC(), userID, tokenHash, userID)
+	return err
+}
+
+func (s *tokenStore) RevokeAccessToken(ctx context.Context, jti string, userID int, expiresAt time.Time) error {
+	query := "INSERT IGNORE INTO revoked_tokens (jti, user_id, expires_at) VALUES (?, ?, ?)"
+	if _, err := s.db.ExecContext(ctx, query, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	// Token yang sudah kedaluwarsa tidak perlu disimpan lagi
+	_, err := s.db.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < ?", time.Now().UTC())
+	return err
+}
+
+func (s *tokenStore) AccessTokenStatus(ctx context.Context, jti string, userID int) (bool, *time.Time, error) {
+	var validAfter sql.NullTime
+	var revoked bool
+	query := "SELECT u.tokens_valid_after, EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = ?) FROM users u WHERE u.id = ?"
+	if err := s.db.QueryRowContext(ctx, query, jti, userID).Scan(&validAfter, &revoked); err != nil {
+		return false, nil, notFound(err)
+	}
+	return revoked, nullTime(validAfter), nil
+}
+
+func (s *tokenStore) RevokeAllForUser(ctx context.Context, userID int) error {
+	// iat JWT dalam detik, jadi batasnya juga dibulatkan ke detik
+	now := time.Now().UTC().Truncate(time.Second)
+	return s.withTx(ctx, func(tx queryer) error {
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET tokens_valid_after = ? WHERE id = ?", now, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL", now, userID)
+		return err
+	})
+}
+
+func (s *tokenStore) ListAccessTokens(ctx context.Context, userID int) ([]models.PersonalAccessToken, error) {
+	query := "SELECT id, name, token_prefix, scopes, expires_at, last_used_at, created_at FROM personal_access_tokens WHERE user_id = ? ORDER BY created_at DESC"
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []models.PersonalAccessToken{}
+	for rows.Next() {
+		var token models.PersonalAccessToken
+		var scopes string
+		var expiresAt, lastUsedAt sql.NullTime
+		if err := rows.Scan(&token.ID, &token.Name, &token.Prefix, &scopes, &expiresAt, &lastUsedAt, &token.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		token.Scopes = strings.Split(scopes, ",")
+		token.ExpiresAt = nullTime(expiresAt)
+		token.LastUsedAt = nullTime(lastUsedAt)
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (s *tokenStore) CreateAccessToken(ctx context.Context, userID int, token *models.PersonalAccessToken, tokenHash string) error {
+	var expiresAt interface{}
+	if token.ExpiresAt != nil {
+		expiresAt = token.ExpiresAt.UTC()
+	}
+
+	query := "INSERT INTO personal_access_tokens (user_id, name, token_prefix, token_hash, scopes, expires_at) VALUES (?, ?, ?, ?, ?, ?)"
+	id, err := s.db.insertID(ctx, query, userID, token.Name, token.Prefix, tokenHash, strings.Join(token.Scopes, ","), expiresAt)
+	if err != nil {
+		return err
+	}
+
+	token.ID = id
+	return s.db.QueryRowContext(ctx, "SELECT created_at FROM personal_access_tokens WHERE id = ?", id).Scan(&token.CreatedAt)
+}
+
+func (s *tokenStore) UseAccessToken(ctx context.Context, tokenHash string) (int, []string, error) {
+	var id, userID int
+	var scopes string
+	var expiresAt sql.NullTime
+	query := "SELECT t.id, t.user_id, t.scopes, t.expires_at FROM personal_access_tokens t INNER JOIN users u ON u.id = t.user_id WHERE t.token_hash = ? AND u.disabled_at IS NULL"
+	if err := s.db.QueryRowContext(ctx, query, tokenHash).Scan(&id, &userID, &scopes, &expiresAt); err != nil {
+		return 0, nil, notFound(err)
+	}
+
+	now := time.Now().UTC()
+	if expiresAt.Valid && now.After(expiresAt.Time) {
+		return 0, nil, store.ErrNotFound
+	}
+
+	// Gagal mencatat last_used_at tidak perlu menggagalkan request
+	s.db.ExecContext(ctx, "UPDATE personal_access_tokens SET last_used_at = ? WHERE id = ?", now, id)
+
+	return userID, strings.Split(scopes, ","), nil
+}
+
+func (s *tokenStore) DeleteAccessToken(ctx context.Context, userID, tokenID int) error {
+	return execAffected(ctx, s.db, "DELETE FROM personal_access_tokens WHERE id = ? AND user_id = ?", tokenID, userID)
+}
+
+func (s *tokenStore) CreateLoginChallenge(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	// Challenge lama yang sudah kedaluwarsa tidak perlu disimpan lagi
+	s.db.ExecContext(ctx, "DELETE FROM login_challenges WHERE user_id = ? AND expires_at < ?", userID, time.Now().UTC())
+
+	query := "INSERT INTO login_challenges (user_id, token_hash, expires_at) VALUES (?, ?, ?)"
+	_, err := s.db.ExecContext(ctx, query, userID, tokenHash, expiresAt)
+	return err
+}
+
+func (s *tokenStore) LoginChallenge(ctx context.Context, tokenHash string) (store.LoginChallenge, error) {
+	var c store.LoginChallenge
+	query := "SELECT id, user_id, attempts, expires_at FROM login_challenges WHERE token_hash = ?"
+	err := s.db.QueryRowContext(ctx, query, tokenHash).Scan(&c.ID, &c.UserID, &c.Attempts, &c.ExpiresAt)
+	return c, notFound(err)
+}
+
+func (s *tokenStore) AttemptLoginChallenge(ctx context.Context, challengeID, maxAttempts int) error {
+	// Cek dan tambah dalam satu statement supaya request paralel tidak bisa melewati batas
+	query := "UPDATE login_challenges SET attempts = attempts + 1 WHERE id = ? AND attempts < ?"
+	return execAffected(ctx, s.db, query, challengeID, maxAttempts)
+}
+
+func (s *tokenStore) ConsumeLoginChallenge(ctx context.Context, challengeID int) error {
+	return execAffected(ctx, s.db, "DELETE FROM login_challenges WHERE id = ?", challengeID)
+}
+
+func (s *tokenStore) CreateOIDCState(ctx context.Context, state store.OIDCState) error {
+	s.db.ExecContext(ctx, "DELETE FROM oidc_states WHERE expires_at < ?", time.Now().UTC())
+
+	query := "INSERT INTO oidc_states (state_hash, nonce, code_verifier, expires_at) VALUES (?, ?, ?, ?)"
+	_, err := s.db.ExecContext(ctx, query, state.StateHash, state.Nonce, state.CodeVerifier, state.ExpiresAt)
+	return err
+}
+
+func (s *tokenStore) ConsumeOIDCState(ctx context.Context, stateHash string) (store.OIDCState, error) {
+	state := store.OIDCState{StateHash: stateHash}
+	query := "SELECT nonce, code_verifier, expires_at FROM oidc_states WHERE state_hash = ?"
+	if err := s.db.QueryRowContext(ctx, query, stateHash).Scan(&state.Nonce, &state.CodeVerifier, &state.ExpiresAt); err != nil {
+		return store.OIDCState{}, notFound(err)
+	}
+
+	// Jika tidak ada baris terhapus, state sudah dipakai request lain di saat bersamaan
+	if err := execAffected(ctx, s.db, "DELETE FROM oidc_states WHERE state_hash = ?", stateHash); err != nil {
+		return store.OIDCState{}, err
+	}
+	return state, nil
+}
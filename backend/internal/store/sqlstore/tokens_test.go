@@ -0,0 +1,59 @@
+package sqlstore_test
+
+import (
+	"context"
+	"errors"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"notes-api/internal/store/storetest"
+	"testing"
+	"time"
+)
+
+// Jika successor gagal disimpan, rotasi token lama ikut dibatalkan sehingga token lama
+// masih bisa dipakai, bukan sesi yang hilang karena token lama sudah tercatat dipakai
+func TestRotateRefreshTokenIsAtomic(t *testing.T) {
+	st := storetest.OpenSQLite(t)
+	ctx := context.Background()
+
+	user := models.User{Username: "budi", Email: "budi@example.com", PasswordHash: "x"}
+	if err := st.Users.Create(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().UTC().Add(time.Hour)
+	first := store.RefreshToken{UserID: user.ID, FamilyID: "family", TokenHash: "hash-1", ExpiresAt: expiresAt}
+	if err := st.Tokens.CreateRefreshToken(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hash yang sama dengan token lama melanggar unique key, jadi insert successor gagal
+	if _, err := st.Tokens.RotateRefreshToken(ctx, "hash-1", store.RefreshToken{TokenHash: "hash-1", ExpiresAt: expiresAt}); err == nil {
+		t.Fatal("rotasi dengan successor duplikat seharusnya gagal")
+	}
+
+	prior, err := st.Tokens.RotateRefreshToken(ctx, "hash-1", store.RefreshToken{TokenHash: "hash-2", ExpiresAt: expiresAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prior.RotatedAt != nil {
+		t.Fatal("rotasi yang gagal tidak boleh menandai token lama sudah dipakai")
+	}
+
+	// Successor tersimpan dengan user dan family token lama
+	next, err := st.Tokens.RotateRefreshToken(ctx, "hash-2", store.RefreshToken{TokenHash: "hash-3", ExpiresAt: expiresAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.UserID != user.ID || next.FamilyID != "family" || next.RotatedAt != nil || next.RevokedAt != nil {
+		t.Fatalf("successor tidak sesuai: %+v", next)
+	}
+
+	// Token yang sudah di-rotate tidak membuat successor baru
+	if _, err := st.Tokens.RotateRefreshToken(ctx, "hash-1", store.RefreshToken{TokenHash: "hash-4", ExpiresAt: expiresAt}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Tokens.RotateRefreshToken(ctx, "hash-4", store.RefreshToken{TokenHash: "hash-5", ExpiresAt: expiresAt}); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("successor dari token yang dipakai ulang tidak boleh tersimpan, err = %v", err)
+	}
+}
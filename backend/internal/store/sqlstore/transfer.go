@@ -0,0 +1,220 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"time"
+)
+
+type transferStore struct {
+	*sqlStore
+}
+
+func (s *transferStore) Export(ctx context.Context, userID int) (models.UserExport, error) {
+	data := models.UserExport{
+		Version:    models.UserExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Folders:    []models.ExportFolder{},
+		Tags:       []models.ExportTag{},
+		Notes:      []models.ExportNote{},
+	}
+
+	// Semua tabel dibaca dalam satu transaksi supaya relasi di file konsisten
+	err := s.withTx(ctx, func(tx queryer) error {
+		var verifiedAt sql.NullTime
+		query := "SELECT username, email, full_name, password_hash, created_at, email_verified_at, revision_retention FROM users WHERE id = ?"
+		u := &data.User
+		if err := tx.QueryRowContext(ctx, query, userID).Scan(&u.Username, &u.Email, &u.FullName, &u.PasswordHash, &u.CreatedAt, &verifiedAt, &u.RevisionRetention); err != nil {
+			return notFound(err)
+		}
+		u.EmailVerifiedAt = nullTime(verifiedAt)
+
+		if err := exportFolders(ctx, tx, userID, &data); err != nil {
+			return err
+		}
+		if err := exportTags(ctx, tx, userID, &data); err != nil {
+			return err
+		}
+		return exportNotes(ctx, tx, userID, &data)
+	})
+
+	return data, err
+}
+
+func exportFolders(ctx context.Context, tx queryer, userID int, data *models.UserExport) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id, parent_id, name, created_at, deleted_at FROM folders WHERE user_id = ? ORDER BY id ASC", userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var folder models.ExportFolder
+		var parentID sql.NullInt64
+		var deletedAt sql.NullTime
+		if err := rows.Scan(&folder.ID, &parentID, &folder.Name, &folder.CreatedAt, &deletedAt); err != nil {
+			return err
+		}
+		folder.ParentID = nullInt(parentID)
+		folder.DeletedAt = nullTime(deletedAt)
+		data.Folders = append(data.Folders, folder)
+	}
+	return rows.Err()
+}
+
+func exportTags(ctx context.Context, tx queryer, userID int, data *models.UserExport) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id, name, created_at FROM tags WHERE user_id = ? ORDER BY id ASC", userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag models.ExportTag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return err
+		}
+		data.Tags = append(data.Tags, tag)
+	}
+	return rows.Err()
+}
+
+func exportNotes(ctx context.Context, tx queryer, userID int, data *models.UserExport) error {
+	query := "SELECT id, folder_id, title, content, is_favorite, version, created_at, updated_at, deleted_at FROM notes WHERE user_id = ? ORDER BY id ASC"
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := map[int]int{} // note ID -> posisi di data.Notes
+	for rows.Next() {
+		note := models.ExportNote{TagIDs: []int{}, Revisions: []models.ExportRevision{}}
+		var folderID sql.NullInt64
+		var deletedAt sql.NullTime
+		if err := rows.Scan(&note.ID, &folderID, &note.Title, &note.Content, &note.IsFavorite, &note.Version, &note.CreatedAt, &note.UpdatedAt, &deletedAt); err != nil {
+			return err
+		}
+		note.FolderID = nullInt(folderID)
+		note.DeletedAt = nullTime(deletedAt)
+		index[note.ID] = len(data.Notes)
+		data.Notes = append(data.Notes, note)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	rows, err = tx.QueryContext(ctx, "SELECT nt.note_id, nt.tag_id FROM note_tags nt INNER JOIN notes n ON n.id = nt.note_id WHERE n.user_id = ? ORDER BY nt.note_id, nt.tag_id", userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var noteID, tagID int
+		if err := rows.Scan(&noteID, &tagID); err != nil {
+			return err
+		}
+		if i, ok := index[noteID]; ok {
+			data.Notes[i].TagIDs = append(data.Notes[i].TagIDs, tagID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	rows, err = tx.QueryContext(ctx, "SELECT note_id, revision, title, content, created_at FROM note_revisions WHERE user_id = ? ORDER BY note_id, revision", userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var noteID int
+		var rev models.ExportRevision
+		if err := rows.Scan(&noteID, &rev.Revision, &rev.Title, &rev.Content, &rev.CreatedAt); err != nil {
+			return err
+		}
+		if i, ok := index[noteID]; ok {
+			data.Notes[i].Revisions = append(data.Notes[i].Revisions, rev)
+		}
+	}
+	return rows.Err()
+}
+
+func (s *transferStore) Import(ctx context.Context, data models.UserExport) (int, error) {
+	if err := store.CheckExport(data); err != nil {
+		return 0, err
+	}
+
+	var userID int
+	err := s.withTx(ctx, func(tx queryer) error {
+		u := data.User
+		query := "INSERT INTO users (username, email, password_hash, full_name, created_at, email_verified_at, revision_retention) VALUES (?, ?, ?, ?, ?, ?, ?)"
+		id, err := tx.insertID(ctx, query, u.Username, u.Email, u.PasswordHash, u.FullName, u.CreatedAt, u.EmailVerifiedAt, u.RevisionRetention)
+		if err != nil {
+			return s.duplicate(err)
+		}
+		userID = id
+
+		// ID di file dipetakan ke ID baru. Parent folder diisi setelah semua folder dibuat
+		// karena urutan di file tidak dijamin parent lebih dulu.
+		folderIDs := map[int]int{}
+		for _, f := range data.Folders {
+			query := "INSERT INTO folders (user_id, name, created_at, deleted_at) VALUES (?, ?, ?, ?)"
+			if folderIDs[f.ID], err = tx.insertID(ctx, query, userID, f.Name, f.CreatedAt, f.DeletedAt); err != nil {
+				return err
+			}
+		}
+		for _, f := range data.Folders {
+			if f.ParentID == nil {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, "UPDATE folders SET parent_id = ? WHERE id = ?", folderIDs[*f.ParentID], folderIDs[f.ID]); err != nil {
+				return err
+			}
+		}
+
+		tagIDs := map[int]int{}
+		for _, t := range data.Tags {
+			if tagIDs[t.ID], err = tx.insertID(ctx, "INSERT INTO tags (user_id, name, created_at) VALUES (?, ?, ?)", userID, t.Name, t.CreatedAt); err != nil {
+				return err
+			}
+		}
+
+		for _, n := range data.Notes {
+			var folderID *int
+			if n.FolderID != nil {
+				id := folderIDs[*n.FolderID]
+				folderID = &id
+			}
+
+			query := "INSERT INTO notes (user_id, folder_id, title, content, is_favorite, version, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+			noteID, err := tx.insertID(ctx, query, userID, folderID, n.Title, n.Content, n.IsFavorite, n.Version, n.CreatedAt, n.UpdatedAt, n.DeletedAt)
+			if err != nil {
+				return err
+			}
+
+			for _, tagID := range n.TagIDs {
+				if _, err := tx.ExecContext(ctx, "INSERT INTO note_tags (note_id, tag_id) VALUES (?, ?)", noteID, tagIDs[tagID]); err != nil {
+					return err
+				}
+			}
+
+			for _, rev := range n.Revisions {
+				query := "INSERT INTO note_revisions (note_id, user_id, revision, title, content, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+				if _, err := tx.ExecContext(ctx, query, noteID, userID, rev.Revision, rev.Title, rev.Content, rev.CreatedAt); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return userID, err
+}
@@ -0,0 +1,186 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"time"
+)
+
+type trashStore struct {
+	*sqlStore
+}
+
+func (s *trashStore) List(ctx context.Context, userID int) ([]models.Note, []models.Folder, error) {
+	noteRows, err := s.db.QueryContext(ctx, "SELECT id, user_id, folder_id, title, content, is_favorite, created_at, updated_at, version, deleted_at FROM notes WHERE user_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC", userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer noteRows.Close()
+
+	notes := []models.Note{}
+	for noteRows.Next() {
+		var note models.Note
+		var folderID sql.NullInt64
+		var deletedAt time.Time
+		err := noteRows.Scan(&note.ID, &note.UserID, &folderID, &note.Title, &note.Content, &note.IsFavorite, &note.CreatedAt, &note.UpdatedAt, &note.Version, &deletedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		note.FolderID = nullInt(folderID)
+		note.DeletedAt = &deletedAt
+		notes = append(notes, note)
+	}
+	if err := noteRows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	folderRows, err := s.db.QueryContext(ctx, "SELECT id, user_id, parent_id, name, created_at, deleted_at FROM folders WHERE user_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC", userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer folderRows.Close()
+
+	folders := []models.Folder{}
+	for folderRows.Next() {
+		var folder models.Folder
+		var parentID sql.NullInt64
+		var deletedAt time.Time
+		if err := folderRows.Scan(&folder.ID, &folder.UserID, &parentID, &folder.Name, &folder.CreatedAt, &deletedAt); err != nil {
+			return nil, nil, err
+		}
+		folder.ParentID = nullInt(parentID)
+		folder.DeletedAt = &deletedAt
+		folders = append(folders, folder)
+	}
+
+	return notes, folders, folderRows.Err()
+}
+
+func (s *trashStore) RestoreNote(ctx context.Context, userID, noteID int) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		var folderID sql.NullInt64
+		err := tx.QueryRowContext(ctx, "SELECT folder_id FROM notes WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL FOR UPDATE", noteID, userID).Scan(&folderID)
+		if err != nil {
+			return notFound(err)
+		}
+
+		// Folder-nya masih di trash: catatan dipindah ke luar folder supaya tetap terlihat
+		if folderID.Valid {
+			if err := checkFolder(ctx, tx, userID, int(folderID.Int64)); err == store.ErrFolderNotFound {
+				folderID = sql.NullInt64{}
+			} else if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE notes SET deleted_at = NULL, folder_id = ? WHERE id = ? AND user_id = ?", folderID, noteID, userID)
+		return err
+	})
+}
+
+func (s *trashStore) RestoreFolder(ctx context.Context, userID, folderID int) (int, int, error) {
+	var restoredFolders, restoredNotes int
+	err := s.withTx(ctx, func(tx queryer) error {
+		var deletedAt time.Time
+		var parentID sql.NullInt64
+		err := tx.QueryRowContext(ctx, "SELECT deleted_at, parent_id FROM folders WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL FOR UPDATE", folderID, userID).Scan(&deletedAt, &parentID)
+		if err != nil {
+			return notFound(err)
+		}
+
+		// Parent-nya masih di trash: folder dipindah ke root
+		if parentID.Valid {
+			if err := checkFolder(ctx, tx, userID, int(parentID.Int64)); err == store.ErrFolderNotFound {
+				parentID = sql.NullInt64{}
+			} else if err != nil {
+				return err
+			}
+		}
+
+		// Sub-folder yang dihapus bersamaan (mode cascade) punya deleted_at yang sama persis
+		rows, err := tx.QueryContext(ctx, "SELECT id, parent_id FROM folders WHERE user_id = ? AND deleted_at = ?", userID, deletedAt)
+		if err != nil {
+			return err
+		}
+		children := map[int][]int{}
+		for rows.Next() {
+			var id int
+			var pid sql.NullInt64
+			if err := rows.Scan(&id, &pid); err != nil {
+				rows.Close()
+				return err
+			}
+			if pid.Valid {
+				children[int(pid.Int64)] = append(children[int(pid.Int64)], id)
+			}
+		}
+		rows.Close()
+
+		ids := store.SubtreeIDs(children, folderID)
+		args := append([]interface{}{userID, deletedAt}, intArgs(ids)...)
+
+		if _, err := tx.ExecContext(ctx, "UPDATE folders SET parent_id = ? WHERE id = ? AND user_id = ?", parentID, folderID, userID); err != nil {
+			return err
+		}
+
+		query := "UPDATE folders SET deleted_at = NULL WHERE user_id = ? AND deleted_at = ? AND id IN (" + placeholders(len(ids)) + ")"
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		// Catatan yang dihapus bersamaan dengan folder punya deleted_at yang sama persis
+		query = "UPDATE notes SET deleted_at = NULL WHERE user_id = ? AND deleted_at = ? AND folder_id IN (" + placeholders(len(ids)) + ")"
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		n, _ := result.RowsAffected()
+		restoredFolders, restoredNotes = len(ids), int(n)
+		return nil
+	})
+
+	return restoredFolders, restoredNotes, err
+}
+
+func (s *trashStore) DeleteNote(ctx context.Context, userID, noteID int) error {
+	return execAffected(ctx, s.db, "DELETE FROM notes WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL", noteID, userID)
+}
+
+func (s *trashStore) DeleteFolder(ctx context.Context, userID, folderID int) error {
+	return execAffected(ctx, s.db, "DELETE FROM folders WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL", folderID, userID)
+}
+
+func (s *trashStore) Empty(ctx context.Context, userID int) (int64, error) {
+	return s.purge(ctx, "user_id = ?", userID)
+}
+
+func (s *trashStore) PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.purge(ctx, "deleted_at < ?", cutoff)
+}
+
+// purge menghapus permanen item trash yang cocok dengan kondisi where.
+// Catatan dihapus lebih dulu supaya relasi note_tags ikut terhapus lewat ON DELETE CASCADE.
+func (s *trashStore) purge(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	var deleted int64
+	err := s.withTx(ctx, func(tx queryer) error {
+		notes, err := tx.ExecContext(ctx, "DELETE FROM notes WHERE deleted_at IS NOT NULL AND "+where, args...)
+		if err != nil {
+			return err
+		}
+
+		folders, err := tx.ExecContext(ctx, "DELETE FROM folders WHERE deleted_at IS NOT NULL AND "+where, args...)
+		if err != nil {
+			return err
+		}
+
+		n1, _ := notes.RowsAffected()
+		n2, _ := folders.RowsAffected()
+		deleted = n1 + n2
+		return nil
+	})
+
+	return deleted, err
+}
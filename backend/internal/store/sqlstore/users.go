@@ -0,0 +1,302 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"notes-api/internal/models"
+	"notes-api/internal/store"
+	"time"
+)
+
+type userStore struct {
+	*sqlStore
+}
+
+const userColumns = "id, username, email, password_hash, full_name, created_at, email_verified_at, totp_enabled_at IS NOT NULL, disabled_at"
+
+func (s *userStore) Create(ctx context.Context, user *models.User) error {
+	query := "INSERT INTO users (username, email, password_hash, full_name, email_verified_at) VALUES (?, ?, ?, ?, ?)"
+	id, err := s.db.insertID(ctx, query, user.Username, user.Email, user.PasswordHash, user.FullName, user.EmailVerifiedAt)
+	if err != nil {
+		return s.duplicate(err)
+	}
+
+	user.ID = id
+	return s.db.QueryRowContext(ctx, "SELECT created_at FROM users WHERE id = ?", id).Scan(&user.CreatedAt)
+}
+
+func (s *userStore) Get(ctx context.Context, userID int) (models.User, error) {
+	user, err := scanUser(s.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = ?", userID))
+	return user, notFound(err)
+}
+
+func (s *userStore) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	user, err := scanUser(s.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE email = ?", email))
+	return user, notFound(err)
+}
+
+func (s *userStore) List(ctx context.Context) ([]models.User, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users ORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (s *userStore) UsernameTaken(ctx context.Context, username string, exceptID int) (bool, error) {
+	return exists(ctx, s.db, "SELECT COUNT(*) FROM users WHERE username = ? AND id <> ?", username, exceptID)
+}
+
+func (s *userStore) EmailTaken(ctx context.Context, email string, exceptID int) (bool, error) {
+	return exists(ctx, s.db, "SELECT COUNT(*) FROM users WHERE email = ? AND id <> ?", email, exceptID)
+}
+
+func (s *userStore) UpdateProfile(ctx context.Context, userID int, username, fullName string) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE users SET username = ?, full_name = ? WHERE id = ?", username, fullName, userID)
+	return s.duplicate(err)
+}
+
+func (s *userStore) SetPassword(ctx context.Context, userID int, passwordHash string) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE users SET password_hash = ? WHERE id = ?", passwordHash, userID)
+	return err
+}
+
+func (s *userStore) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	// Cek dulu karena RowsAffected bernilai 0 juga jika statusnya tidak berubah
+	found, err := exists(ctx, s.db, "SELECT COUNT(*) FROM users WHERE id = ?", userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return store.ErrNotFound
+	}
+
+	// Waktu nonaktif pertama dipertahankan jika disable dijalankan ulang
+	query, args := "UPDATE users SET disabled_at = NULL WHERE id = ?", []interface{}{userID}
+	if disabled {
+		query, args = "UPDATE users SET disabled_at = COALESCE(disabled_at, ?) WHERE id = ?", []interface{}{time.Now().UTC(), userID}
+	}
+	_, err = s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (s *userStore) CreateEmailVerification(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := "INSERT INTO email_verifications (user_id, token_hash, expires_at) VALUES (?, ?, ?)"
+	_, err := s.db.ExecContext(ctx, query, userID, tokenHash, expiresAt)
+	return err
+}
+
+func (s *userStore) VerifyEmail(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := s.withTx(ctx, func(tx queryer) error {
+		var expiresAt time.Time
+		query := "SELECT user_id, expires_at FROM email_verifications WHERE token_hash = ? FOR UPDATE"
+		if err := tx.QueryRowContext(ctx, query, tokenHash).Scan(&userID, &expiresAt); err != nil {
+			return notFound(err)
+		}
+		if time.Now().After(expiresAt) {
+			return store.ErrNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET email_verified_at = COALESCE(email_verified_at, ?) WHERE id = ?", time.Now().UTC(), userID); err != nil {
+			return err
+		}
+
+		// Semua link verifikasi milik user ini tidak berlaku lagi
+		_, err := tx.ExecContext(ctx, "DELETE FROM email_verifications WHERE user_id = ?", userID)
+		return err
+	})
+
+	return userID, err
+}
+
+func (s *userStore) RequestEmailChange(ctx context.Context, userID int, newEmail, tokenHash string, expiresAt time.Time) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		// Hanya permintaan terakhir yang berlaku
+		if _, err := tx.ExecContext(ctx, "DELETE FROM email_changes WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+
+		query := "INSERT INTO email_changes (user_id, new_email, token_hash, expires_at) VALUES (?, ?, ?, ?)"
+		_, err := tx.ExecContext(ctx, query, userID, newEmail, tokenHash, expiresAt)
+		return err
+	})
+}
+
+func (s *userStore) ConfirmEmailChange(ctx context.Context, tokenHash string) (string, error) {
+	var newEmail string
+	var expired bool
+	err := s.withTx(ctx, func(tx queryer) error {
+		var changeID, userID int
+		var expiresAt time.Time
+		query := "SELECT id, user_id, new_email, expires_at FROM email_changes WHERE token_hash = ? FOR UPDATE"
+		if err := tx.QueryRowContext(ctx, query, tokenHash).Scan(&changeID, &userID, &newEmail, &expiresAt); err != nil {
+			return notFound(err)
+		}
+
+		// Token hanya bisa dipakai sekali, termasuk yang sudah kedaluwarsa
+		if _, err := tx.ExecContext(ctx, "DELETE FROM email_changes WHERE id = ?", changeID); err != nil {
+			return err
+		}
+		if time.Now().After(expiresAt) {
+			expired = true
+			return nil
+		}
+
+		// Membuka link di alamat baru sekaligus membuktikan email itu milik user
+		_, err := tx.ExecContext(ctx, "UPDATE users SET email = ?, email_verified_at = ? WHERE id = ?", newEmail, time.Now().UTC(), userID)
+		return s.duplicate(err)
+	})
+
+	if err == nil && expired {
+		return "", store.ErrExpired
+	}
+	return newEmail, err
+}
+
+func (s *userStore) CreatePasswordReset(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := "INSERT INTO password_resets (user_id, token_hash, expires_at) VALUES (?, ?, ?)"
+	_, err := s.db.ExecContext(ctx, query, userID, tokenHash, expiresAt)
+	return err
+}
+
+func (s *userStore) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (int, error) {
+	var userID int
+	err := s.withTx(ctx, func(tx queryer) error {
+		var resetID int
+		var expiresAt time.Time
+		var usedAt sql.NullTime
+		query := "SELECT id, user_id, expires_at, used_at FROM password_resets WHERE token_hash = ? FOR UPDATE"
+		if err := tx.QueryRowContext(ctx, query, tokenHash).Scan(&resetID, &userID, &expiresAt, &usedAt); err != nil {
+			return notFound(err)
+		}
+		if usedAt.Valid || time.Now().After(expiresAt) {
+			return store.ErrNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET password_hash = ? WHERE id = ?", passwordHash, userID); err != nil {
+			return err
+		}
+
+		// Tandai token ini terpakai dan buang token reset lain milik user yang sama
+		if _, err := tx.ExecContext(ctx, "UPDATE password_resets SET used_at = ? WHERE id = ?", time.Now().UTC(), resetID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM password_resets WHERE user_id = ? AND id <> ?", userID, resetID)
+		return err
+	})
+
+	return userID, err
+}
+
+func (s *userStore) TwoFactor(ctx context.Context, userID int) (store.TwoFactor, error) {
+	var tf store.TwoFactor
+	var secret sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT totp_secret, totp_enabled_at IS NOT NULL FROM users WHERE id = ?", userID).Scan(&secret, &tf.Enabled)
+	tf.Secret = secret.String
+	return tf, notFound(err)
+}
+
+func (s *userStore) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE users SET totp_secret = ?, totp_last_step = NULL WHERE id = ? AND totp_enabled_at IS NULL", secret, userID)
+	return err
+}
+
+func (s *userStore) EnableTwoFactor(ctx context.Context, userID int, secret string, step int64, recoveryHashes []string) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		// Kondisi di WHERE mencegah dua konfirmasi bersamaan atau setup ulang di tengah jalan
+		query := "UPDATE users SET totp_enabled_at = ?, totp_last_step = ? WHERE id = ? AND totp_secret = ? AND totp_enabled_at IS NULL"
+		result, err := tx.ExecContext(ctx, query, time.Now().UTC(), step, userID, secret)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return store.ErrConflict
+		}
+
+		return replaceRecoveryCodes(ctx, tx, userID, recoveryHashes)
+	})
+}
+
+func (s *userStore) DisableTwoFactor(ctx context.Context, userID int) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL WHERE id = ?", userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+func (s *userStore) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	// Satu UPDATE bersyarat supaya kode yang sama tidak bisa dipakai dua request bersamaan
+	query := "UPDATE users SET totp_last_step = ? WHERE id = ? AND totp_enabled_at IS NOT NULL AND (totp_last_step IS NULL OR totp_last_step < ?)"
+	result, err := s.db.ExecContext(ctx, query, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n == 1, nil
+}
+
+func (s *userStore) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	query := "UPDATE recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL"
+	result, err := s.db.ExecContext(ctx, query, time.Now().UTC(), userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n == 1, nil
+}
+
+func (s *userStore) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	return s.withTx(ctx, func(tx queryer) error {
+		return replaceRecoveryCodes(ctx, tx, userID, codeHashes)
+	})
+}
+
+func (s *userStore) FindIdentity(ctx context.Context, issuer, subject string) (int, error) {
+	var userID int
+	err := s.db.QueryRowContext(ctx, "SELECT user_id FROM user_identities WHERE issuer = ? AND subject = ?", issuer, subject).Scan(&userID)
+	return userID, notFound(err)
+}
+
+func (s *userStore) LinkIdentity(ctx context.Context, userID int, issuer, subject, email string) error {
+	query := "INSERT INTO user_identities (user_id, issuer, subject, email) VALUES (?, ?, ?, ?)"
+	_, err := s.db.ExecContext(ctx, query, userID, issuer, subject, email)
+	return s.duplicate(err)
+}
+
+// replaceRecoveryCodes membuang recovery code lama user lalu menyimpan hash yang baru
+func replaceRecoveryCodes(ctx context.Context, tx queryer, userID int, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO recovery_codes (user_id, code_hash) VALUES (?, ?)", userID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanUser(row scanner) (models.User, error) {
+	var user models.User
+	var verifiedAt, disabledAt sql.NullTime
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.FullName, &user.CreatedAt, &verifiedAt, &user.TwoFactorEnabled, &disabledAt)
+	user.EmailVerifiedAt = nullTime(verifiedAt)
+	user.DisabledAt = nullTime(disabledAt)
+	return user, err
+}
@@ -0,0 +1,51 @@
+// Package store mendefinisikan akses data aplikasi sebagai interface (repository),
+// supaya handler tidak bergantung pada database tertentu. Implementasinya ada di
+// sub-package sqlstore (MySQL, PostgreSQL, SQLite) dan memory (tanpa database, untuk
+// test HTTP).
+package store
+
+import (
+	"errors"
+	"notes-api/internal/ratelimit"
+)
+
+// Store mengumpulkan semua repository yang dipakai handler dan middleware
+type Store struct {
+	Users     UserStore
+	Notes     NoteStore
+	Folders   FolderStore
+	Tags      TagStore
+	Revisions RevisionStore
+	Trash     TrashStore
+	Tokens    TokenStore
+	Transfer  TransferStore
+
+	// LoginAttempts menyimpan hitungan login gagal jika RATE_LIMIT_STORE=database
+	LoginAttempts ratelimit.Store
+}
+
+// Error yang dikembalikan semua implementasi store. Handler memetakannya ke status HTTP.
+var (
+	ErrNotFound         = errors.New("data tidak ditemukan")
+	ErrDuplicate        = errors.New("data dengan nilai unik yang sama sudah ada")
+	ErrConflict         = errors.New("data sudah diubah di tempat lain")
+	ErrExpired          = errors.New("token sudah kedaluwarsa")
+	ErrFolderNotFound   = errors.New("folder tidak ditemukan")
+	ErrTagNotFound      = errors.New("tag tidak ditemukan")
+	ErrRevisionNotFound = errors.New("revisi tidak ditemukan")
+	ErrFolderCycle      = errors.New("folder tidak bisa dipindah ke dalam dirinya sendiri atau sub-foldernya")
+	ErrInvalidExport    = errors.New("file export tidak valid")
+)
+
+// UniqueIDs membuang ID duplikat dengan tetap menjaga urutan
+func UniqueIDs(ids []int) []int {
+	seen := map[int]bool{}
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,571 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"notes-api/internal/models"
+	"notes-api/internal/ratelimit"
+	"notes-api/internal/store"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// RunContract menjalankan perilaku yang harus sama di semua implementasi store (memory,
+// MySQL, PostgreSQL, SQLite). open dipanggil sekali per subtest dan harus mengembalikan
+// store kosong. Setiap bagian yang diterjemahkan dialect ikut teruji: placeholder,
+// INSERT IGNORE, ID baris baru, LIKE tanpa membedakan huruf besar/kecil, dan full-text search.
+func RunContract(t *testing.T, open func(t testing.TB) *store.Store) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, st *store.Store)
+	}{
+		{"Users", testUsers},
+		{"NotesCRUD", testNotesCRUD},
+		{"NotesListFilter", testNotesListFilter},
+		{"NotesPagination", testNotesPagination},
+		{"NotesSearch", testNotesSearch},
+		{"Tags", testTags},
+		{"FoldersAndTrash", testFoldersAndTrash},
+		{"Tokens", testTokens},
+		{"LoginAttempts", testLoginAttempts},
+	}
+	for _, tt := range tests {
+		fn := tt.fn
+		t.Run(tt.name, func(t *testing.T) {
+			fn(t, open(t))
+		})
+	}
+}
+
+func testUsers(t *testing.T, st *store.Store) {
+	ctx := context.Background()
+	budi := createUser(t, st, "budi")
+	ani := createUser(t, st, "ani")
+	if budi == 0 || ani == 0 || budi == ani {
+		t.Fatalf("ID user baru tidak valid: %d, %d", budi, ani)
+	}
+
+	// Username dan email unik tanpa membedakan huruf besar/kecil
+	for _, user := range []models.User{
+		{Username: "BUDI", Email: "lain@example.com", PasswordHash: "x"},
+		{Username: "lain", Email: "Budi@Example.com", PasswordHash: "x"},
+	} {
+		if err := st.Users.Create(ctx, &user); !errors.Is(err, store.ErrDuplicate) {
+			t.Fatalf("Create(%s, %s): err = %v, seharusnya ErrDuplicate", user.Username, user.Email, err)
+		}
+	}
+
+	user, err := st.Users.GetByEmail(ctx, "BUDI@example.com")
+	if err != nil || user.ID != budi || user.Username != "budi" {
+		t.Fatalf("GetByEmail = %+v, %v", user, err)
+	}
+	if _, err := st.Users.Get(ctx, ani+budi); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Get user yang tidak ada: err = %v, seharusnya ErrNotFound", err)
+	}
+
+	if taken, err := st.Users.UsernameTaken(ctx, "Ani", budi); err != nil || !taken {
+		t.Fatalf("UsernameTaken(Ani) = %v, %v", taken, err)
+	}
+	if taken, err := st.Users.UsernameTaken(ctx, "ani", ani); err != nil || taken {
+		t.Fatalf("UsernameTaken milik sendiri = %v, %v", taken, err)
+	}
+	if err := st.Users.UpdateProfile(ctx, ani, "Budi", ""); !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("UpdateProfile ke username yang dipakai: err = %v, seharusnya ErrDuplicate", err)
+	}
+}
+
+func testNotesCRUD(t *testing.T, st *store.Store) {
+	ctx := context.Background()
+	budi := createUser(t, st, "budi")
+	ani := createUser(t, st, "ani")
+
+	first := createNote(t, st, budi, "Belanja", "susu")
+	second := createNote(t, st, budi, "Rapat", "agenda")
+	if first.ID == 0 || second.ID == 0 || first.ID == second.ID {
+		t.Fatalf("ID catatan baru tidak valid: %d, %d", first.ID, second.ID)
+	}
+	if first.Version != 1 || first.CreatedAt.IsZero() {
+		t.Fatalf("catatan baru tanpa version atau waktu pembuatan: %+v", first)
+	}
+
+	got, err := st.Notes.Get(ctx, budi, first.ID)
+	if err != nil || got.Title != "Belanja" || got.Content != "susu" {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	if _, err := st.Notes.Get(ctx, ani, first.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Get catatan user lain: err = %v, seharusnya ErrNotFound", err)
+	}
+
+	updated, err := st.Notes.Update(ctx, budi, first.ID, func(note *models.Note) error {
+		note.Title = "Belanja mingguan"
+		note.IsFavorite = true
+		return nil
+	})
+	if err != nil || updated.Title != "Belanja mingguan" || !updated.IsFavorite || updated.Version != 2 {
+		t.Fatalf("Update = %+v, %v", updated, err)
+	}
+
+	// Error dari fn membatalkan update
+	errAbort := errors.New("batal")
+	if _, err := st.Notes.Update(ctx, budi, first.ID, func(note *models.Note) error {
+		note.Title = "Tidak tersimpan"
+		return errAbort
+	}); !errors.Is(err, errAbort) {
+		t.Fatalf("Update dengan fn gagal: err = %v", err)
+	}
+	if got, _ := st.Notes.Get(ctx, budi, first.ID); got.Title != "Belanja mingguan" || got.Version != 2 {
+		t.Fatalf("update yang dibatalkan ikut tersimpan: %+v", got)
+	}
+	if _, err := st.Notes.Update(ctx, ani, first.ID, func(*models.Note) error { return nil }); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Update catatan user lain: err = %v, seharusnya ErrNotFound", err)
+	}
+
+	// Judul berubah, jadi isi lama tersimpan sebagai revisi
+	revisions, err := st.Revisions.List(ctx, budi, first.ID)
+	if err != nil || len(revisions) != 1 || revisions[0].Title != "Belanja" {
+		t.Fatalf("revisi setelah update = %+v, %v", revisions, err)
+	}
+
+	if err := st.Notes.Delete(ctx, ani, first.ID, nil); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Delete catatan user lain: err = %v, seharusnya ErrNotFound", err)
+	}
+	if err := st.Notes.Delete(ctx, budi, first.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Notes.Get(ctx, budi, first.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Get catatan di trash: err = %v, seharusnya ErrNotFound", err)
+	}
+	if ids := noteIDs(listNotes(t, st, budi, store.NoteFilter{})); !reflect.DeepEqual(ids, []int{second.ID}) {
+		t.Fatalf("List setelah delete = %v, seharusnya [%d]", ids, second.ID)
+	}
+}
+
+func testNotesListFilter(t *testing.T, st *store.Store) {
+	ctx := context.Background()
+	budi := createUser(t, st, "budi")
+	ani := createUser(t, st, "ani")
+
+	folder := models.Folder{UserID: budi, Name: "Dapur"}
+	if err := st.Folders.Create(ctx, &folder); err != nil {
+		t.Fatal(err)
+	}
+	recipe := createNoteIn(t, st, budi, &folder.ID, "Resep Rendang", "daging sapi dan SANTAN")
+	shopping := createNote(t, st, budi, "Belanja", "beli santan kelapa")
+	meeting := createNote(t, st, budi, "Rapat", "agenda mingguan")
+	createNote(t, st, ani, "Santan milik Ani", "santan")
+
+	if _, err := st.Notes.Update(ctx, budi, shopping.ID, func(note *models.Note) error {
+		note.IsFavorite = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter store.NoteFilter
+		want   []int
+	}{
+		{"tanpa filter", store.NoteFilter{}, []int{recipe.ID, shopping.ID, meeting.ID}},
+		{"search tanpa membedakan huruf besar/kecil", store.NoteFilter{Search: "santan"}, []int{recipe.ID, shopping.ID}},
+		{"search huruf besar", store.NoteFilter{Search: "RENDANG"}, []int{recipe.ID}},
+		{"search sebagian kata", store.NoteFilter{Search: "elap"}, []int{shopping.ID}},
+		{"search tanpa hasil", store.NoteFilter{Search: "tidak ada"}, nil},
+		{"folder", store.NoteFilter{FolderIDs: []int{folder.ID}}, []int{recipe.ID}},
+		{"favorit", store.NoteFilter{Favorite: true}, []int{shopping.ID}},
+		{"favorit dan search", store.NoteFilter{Favorite: true, Search: "rendang"}, nil},
+	}
+	for _, tt := range tests {
+		if got := noteIDs(listNotes(t, st, budi, tt.filter)); !sameIDs(got, tt.want) {
+			t.Errorf("%s: List = %v, seharusnya %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Folder milik user lain tidak bisa dipakai
+	note := models.Note{UserID: ani, Title: "Titip", FolderID: &folder.ID}
+	if err := st.Notes.Create(ctx, &note); !errors.Is(err, store.ErrFolderNotFound) {
+		t.Fatalf("Create di folder user lain: err = %v, seharusnya ErrFolderNotFound", err)
+	}
+}
+
+func testNotesPagination(t *testing.T, st *store.Store) {
+	budi := createUser(t, st, "budi")
+
+	// Judul sengaja tidak urut dan berbeda huruf besar/kecil
+	var want []int
+	byTitle := map[string]int{}
+	for _, title := range []string{"delta", "Alpha", "charlie", "Bravo", "echo"} {
+		note := createNote(t, st, budi, title, "")
+		byTitle[title] = note.ID
+		want = append(want, note.ID)
+	}
+
+	titleOrder := []int{byTitle["Alpha"], byTitle["Bravo"], byTitle["charlie"], byTitle["delta"], byTitle["echo"]}
+	if got := paginate(t, st, budi, store.Page{Limit: 2, Sort: store.SortTitle}); !reflect.DeepEqual(got, titleOrder) {
+		t.Errorf("sort title = %v, seharusnya %v", got, titleOrder)
+	}
+
+	reversed := make([]int, len(titleOrder))
+	for i, id := range titleOrder {
+		reversed[len(titleOrder)-1-i] = id
+	}
+	if got := paginate(t, st, budi, store.Page{Limit: 2, Sort: store.SortTitle, Desc: true}); !reflect.DeepEqual(got, reversed) {
+		t.Errorf("sort title desc = %v, seharusnya %v", got, reversed)
+	}
+
+	// Catatan dibuat dalam detik yang sama, jadi urutan created_at ditentukan ID
+	if got := paginate(t, st, budi, store.Page{Limit: 2, Sort: store.SortCreatedAt}); !reflect.DeepEqual(got, want) {
+		t.Errorf("sort created_at = %v, seharusnya %v", got, want)
+	}
+}
+
+func testNotesSearch(t *testing.T, st *store.Store) {
+	ctx := context.Background()
+	budi := createUser(t, st, "budi")
+	ani := createUser(t, st, "ani")
+
+	recipe := createNote(t, st, budi, "Resep rendang", "daging sapi dimasak dengan santan")
+	shopping := createNote(t, st, budi, "Belanja bulanan", "beli santan, gula merah dan sapi perah")
+	meeting := createNote(t, st, budi, "Rapat proyek", "membahas anggaran kuartal")
+	deleted := createNote(t, st, budi, "Santan basi", "buang")
+	createNote(t, st, ani, "Santan milik Ani", "santan santan")
+	if err := st.Notes.Delete(ctx, budi, deleted.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		terms []store.SearchTerm
+		want  []int
+	}{
+		{"satu kata", []store.SearchTerm{{Text: "santan"}}, []int{recipe.ID, shopping.ID}},
+		{"huruf besar", []store.SearchTerm{{Text: "SANTAN"}}, []int{recipe.ID, shopping.ID}},
+		{"semua kata harus cocok", []store.SearchTerm{{Text: "santan"}, {Text: "gula"}}, []int{shopping.ID}},
+		{"frasa", []store.SearchTerm{{Text: "daging sapi", Phrase: true}}, []int{recipe.ID}},
+		{"frasa tidak berurutan", []store.SearchTerm{{Text: "sapi daging", Phrase: true}}, nil},
+		{"awalan", []store.SearchTerm{{Text: "angg", Prefix: true}}, []int{meeting.ID}},
+		{"pengecualian", []store.SearchTerm{{Text: "santan"}, {Text: "rendang", Exclude: true}}, []int{shopping.ID}},
+		{"tanpa hasil", []store.SearchTerm{{Text: "kambing"}}, nil},
+	}
+	for _, tt := range tests {
+		results, err := st.Notes.Search(ctx, budi, store.SearchQuery{Terms: tt.terms, Limit: 10})
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got []int
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		if !sameIDs(got, tt.want) {
+			t.Errorf("%s: Search = %v, seharusnya %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Skor relevansi selalu positif, berapa pun skala asli dari database-nya
+	all, err := st.Notes.Search(ctx, budi, store.SearchQuery{Terms: []store.SearchTerm{{Text: "sapi"}}, Limit: 10})
+	if err != nil || len(all) != 2 || all[0].Score <= 0 || all[1].Score <= 0 {
+		t.Fatalf("Search skor = %+v, %v", all, err)
+	}
+	for offset, want := range []int{1, 1, 0} {
+		paged, err := st.Notes.Search(ctx, budi, store.SearchQuery{Terms: []store.SearchTerm{{Text: "sapi"}}, Limit: 1, Offset: offset})
+		if err != nil || len(paged) != want {
+			t.Fatalf("Search dengan limit 1 offset %d = %d hasil, %v, seharusnya %d", offset, len(paged), err, want)
+		}
+	}
+}
+
+func testTags(t *testing.T, st *store.Store) {
+	ctx := context.Background()
+	budi := createUser(t, st, "budi")
+	ani := createUser(t, st, "ani")
+
+	important := createTag(t, st, budi, "penting")
+	urgent := createTag(t, st, budi, "mendesak")
+	dup := models.Tag{UserID: budi, Name: "Penting"}
+	if err := st.Tags.Create(ctx, &dup); !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("Create tag duplikat: err = %v, seharusnya ErrDuplicate", err)
+	}
+	// Nama tag hanya unik per user
+	createTag(t, st, ani, "penting")
+
+	both := createNote(t, st, budi, "Dua tag", "")
+	single := createNote(t, st, budi, "Satu tag", "")
+	onlyUrgent := createNote(t, st, budi, "Hanya mendesak", "")
+	other := createNote(t, st, ani, "Milik Ani", "")
+
+	for _, attach := range []struct{ note, tag int }{{both.ID, important.ID}, {both.ID, urgent.ID}, {single.ID, urgent.ID}, {onlyUrgent.ID, urgent.ID}} {
+		if err := st.Tags.Attach(ctx, budi, attach.note, attach.tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := st.Tags.Attach(ctx, budi, both.ID, important.ID); !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("Attach dua kali: err = %v, seharusnya ErrDuplicate", err)
+	}
+	if err := st.Tags.Attach(ctx, ani, other.ID, important.ID); !errors.Is(err, store.ErrTagNotFound) {
+		t.Fatalf("Attach tag user lain: err = %v, seharusnya ErrTagNotFound", err)
+	}
+	if err := st.Tags.Attach(ctx, budi, other.ID, important.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Attach ke catatan user lain: err = %v, seharusnya ErrNotFound", err)
+	}
+
+	// Bulk add_tags melewati relasi yang sudah ada
+	ids, err := st.Notes.Bulk(ctx, budi, models.BulkNoteRequest{NoteIDs: []int{both.ID, single.ID, single.ID, other.ID}, Action: "add_tags", TagIDs: []int{important.ID}})
+	if err != nil || !sameIDs(ids, []int{both.ID, single.ID}) {
+		t.Fatalf("Bulk add_tags = %v, %v", ids, err)
+	}
+
+	// Catatan yang sudah punya tag tujuan tetap dihitung sebagai catatan yang dipindah
+	moved, err := st.Tags.Merge(ctx, budi, urgent.ID, important.ID)
+	if err != nil || moved != 3 {
+		t.Fatalf("Merge = %d, %v, seharusnya 3 catatan dipindah", moved, err)
+	}
+	if _, err := st.Tags.Get(ctx, budi, urgent.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("tag sumber masih ada setelah merge: err = %v", err)
+	}
+
+	tags, err := st.Tags.ForNotes(ctx, budi, []int{both.ID, single.ID, onlyUrgent.ID, other.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags[both.ID]) != 1 || len(tags[single.ID]) != 1 || len(tags[onlyUrgent.ID]) != 1 || tags[onlyUrgent.ID][0].ID != important.ID || len(tags[other.ID]) != 0 {
+		t.Fatalf("ForNotes setelah merge = %+v", tags)
+	}
+
+	list, err := st.Tags.List(ctx, budi)
+	if err != nil || len(list) != 1 || list[0].NoteCount != 3 {
+		t.Fatalf("List tag = %+v, %v", list, err)
+	}
+	if ids := noteIDs(listNotes(t, st, budi, store.NoteFilter{TagID: important.ID})); !sameIDs(ids, []int{both.ID, single.ID, onlyUrgent.ID}) {
+		t.Fatalf("List dengan filter tag = %v", ids)
+	}
+}
+
+func testFoldersAndTrash(t *testing.T, st *store.Store) {
+	ctx := context.Background()
+	budi := createUser(t, st, "budi")
+
+	parent := models.Folder{UserID: budi, Name: "Kerja"}
+	if err := st.Folders.Create(ctx, &parent); err != nil {
+		t.Fatal(err)
+	}
+	child := models.Folder{UserID: budi, Name: "Proyek", ParentID: &parent.ID}
+	if err := st.Folders.Create(ctx, &child); err != nil {
+		t.Fatal(err)
+	}
+	if parent.ID == 0 || child.ID == 0 || parent.ID == child.ID {
+		t.Fatalf("ID folder baru tidak valid: %d, %d", parent.ID, child.ID)
+	}
+	note := createNoteIn(t, st, budi, &child.ID, "Laporan", "")
+
+	if _, err := st.Folders.Update(ctx, budi, parent.ID, func(f *models.Folder) error {
+		f.ParentID = &child.ID
+		return nil
+	}); !errors.Is(err, store.ErrFolderCycle) {
+		t.Fatalf("memindah folder ke sub-foldernya: err = %v, seharusnya ErrFolderCycle", err)
+	}
+
+	count, err := st.Folders.Delete(ctx, budi, parent.ID, true)
+	if err != nil || count != 2 {
+		t.Fatalf("Delete cascade = %d, %v, seharusnya 2 folder", count, err)
+	}
+	if _, err := st.Notes.Get(ctx, budi, note.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("catatan di folder yang dihapus masih aktif: err = %v", err)
+	}
+
+	notes, folders, err := st.Trash.List(ctx, budi)
+	if err != nil || len(notes) != 1 || len(folders) != 2 {
+		t.Fatalf("Trash.List = %d catatan, %d folder, %v", len(notes), len(folders), err)
+	}
+
+	restoredFolders, restoredNotes, err := st.Trash.RestoreFolder(ctx, budi, parent.ID)
+	if err != nil || restoredFolders != 2 || restoredNotes != 1 {
+		t.Fatalf("RestoreFolder = %d folder, %d catatan, %v", restoredFolders, restoredNotes, err)
+	}
+	if got, err := st.Notes.Get(ctx, budi, note.ID); err != nil || got.FolderID == nil || *got.FolderID != child.ID {
+		t.Fatalf("catatan setelah restore = %+v, %v", got, err)
+	}
+
+	if err := st.Notes.Delete(ctx, budi, note.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := st.Trash.Empty(ctx, budi); err != nil || n != 1 {
+		t.Fatalf("Trash.Empty = %d, %v", n, err)
+	}
+}
+
+func testTokens(t *testing.T, st *store.Store) {
+	ctx := context.Background()
+	budi := createUser(t, st, "budi")
+	expiresAt := time.Now().UTC().Add(time.Hour)
+
+	// Mencabut jti yang sama dua kali tidak error (INSERT IGNORE)
+	for i := 0; i < 2; i++ {
+		if err := st.Tokens.RevokeAccessToken(ctx, "jti-1", budi, expiresAt); err != nil {
+			t.Fatalf("RevokeAccessToken ke-%d: %v", i+1, err)
+		}
+	}
+	if revoked, validAfter, err := st.Tokens.AccessTokenStatus(ctx, "jti-1", budi); err != nil || !revoked || validAfter != nil {
+		t.Fatalf("AccessTokenStatus(jti-1) = %v, %v, %v", revoked, validAfter, err)
+	}
+	if revoked, _, err := st.Tokens.AccessTokenStatus(ctx, "jti-2", budi); err != nil || revoked {
+		t.Fatalf("AccessTokenStatus(jti-2) = %v, %v", revoked, err)
+	}
+
+	if err := st.Tokens.RevokeAllForUser(ctx, budi); err != nil {
+		t.Fatal(err)
+	}
+	if _, validAfter, err := st.Tokens.AccessTokenStatus(ctx, "jti-2", budi); err != nil || validAfter == nil {
+		t.Fatalf("tokens_valid_after tidak diset setelah RevokeAllForUser: %v, %v", validAfter, err)
+	}
+
+	// Batas percobaan 2FA dicek dan ditambah sekaligus
+	if err := st.Tokens.CreateLoginChallenge(ctx, budi, "challenge-hash", time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := st.Tokens.LoginChallenge(ctx, "challenge-hash")
+	if err != nil || challenge.UserID != budi {
+		t.Fatalf("LoginChallenge = %+v, %v", challenge, err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := st.Tokens.AttemptLoginChallenge(ctx, challenge.ID, 2); err != nil {
+			t.Fatalf("AttemptLoginChallenge ke-%d: %v", i+1, err)
+		}
+	}
+	if err := st.Tokens.AttemptLoginChallenge(ctx, challenge.ID, 2); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("AttemptLoginChallenge melewati batas: %v, seharusnya ErrNotFound", err)
+	}
+	if challenge, err := st.Tokens.LoginChallenge(ctx, "challenge-hash"); err != nil || challenge.Attempts != 2 {
+		t.Fatalf("attempts = %d, %v, seharusnya 2", challenge.Attempts, err)
+	}
+
+	pat := models.PersonalAccessToken{Name: "cli", Prefix: "nt_abc", Scopes: []string{"notes:read"}}
+	if err := st.Tokens.CreateAccessToken(ctx, budi, &pat, "pat-hash"); err != nil || pat.ID == 0 {
+		t.Fatalf("CreateAccessToken: ID %d, %v", pat.ID, err)
+	}
+	userID, scopes, err := st.Tokens.UseAccessToken(ctx, "pat-hash")
+	if err != nil || userID != budi || !reflect.DeepEqual(scopes, []string{"notes:read"}) {
+		t.Fatalf("UseAccessToken = %d, %v, %v", userID, scopes, err)
+	}
+}
+
+func testLoginAttempts(t *testing.T, st *store.Store) {
+	if st.LoginAttempts == nil {
+		t.Skip("store tanpa LoginAttempts")
+	}
+
+	if state, err := st.LoginAttempts.Get("ip:127.0.0.1"); err != nil || state.Failures != 0 {
+		t.Fatalf("Get key baru = %+v, %v", state, err)
+	}
+
+	// Update pertama membuat baris (INSERT IGNORE), berikutnya memakai baris yang sama
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 1; i <= 2; i++ {
+		state, err := st.LoginAttempts.Update("ip:127.0.0.1", func(s *ratelimit.State) {
+			s.Failures++
+			s.LastFailure = now
+		})
+		if err != nil || state.Failures != i {
+			t.Fatalf("Update ke-%d = %+v, %v", i, state, err)
+		}
+	}
+
+	state, err := st.LoginAttempts.Get("ip:127.0.0.1")
+	if err != nil || state.Failures != 2 || !state.LastFailure.Equal(now) {
+		t.Fatalf("Get = %+v, %v", state, err)
+	}
+	if err := st.LoginAttempts.Delete("ip:127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := st.LoginAttempts.Get("ip:127.0.0.1"); err != nil || state.Failures != 0 {
+		t.Fatalf("Get setelah Delete = %+v, %v", state, err)
+	}
+}
+
+// createUser membuat user dengan email username@example.com lalu mengembalikan ID-nya
+func createUser(t *testing.T, st *store.Store, username string) int {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com", PasswordHash: "x"}
+	if err := st.Users.Create(context.Background(), &user); err != nil {
+		t.Fatalf("membuat user %s: %v", username, err)
+	}
+	return user.ID
+}
+
+func createNote(t *testing.T, st *store.Store, userID int, title, content string) models.Note {
+	t.Helper()
+	return createNoteIn(t, st, userID, nil, title, content)
+}
+
+func createNoteIn(t *testing.T, st *store.Store, userID int, folderID *int, title, content string) models.Note {
+	t.Helper()
+	note := models.Note{UserID: userID, FolderID: folderID, Title: title, Content: content}
+	if err := st.Notes.Create(context.Background(), &note); err != nil {
+		t.Fatalf("membuat catatan %s: %v", title, err)
+	}
+	return note
+}
+
+func createTag(t *testing.T, st *store.Store, userID int, name string) models.Tag {
+	t.Helper()
+	tag := models.Tag{UserID: userID, Name: name}
+	if err := st.Tags.Create(context.Background(), &tag); err != nil {
+		t.Fatalf("membuat tag %s: %v", name, err)
+	}
+	return tag
+}
+
+func listNotes(t *testing.T, st *store.Store, userID int, filter store.NoteFilter) []models.Note {
+	t.Helper()
+	notes, err := st.Notes.List(context.Background(), userID, filter, store.Page{Limit: 100, Sort: store.SortCreatedAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notes
+}
+
+// paginate mengambil semua catatan user halaman demi halaman mengikuti cursor dari
+// catatan terakhir, seperti yang dilakukan handler
+func paginate(t *testing.T, st *store.Store, userID int, page store.Page) []int {
+	t.Helper()
+	var ids []int
+	for i := 0; ; i++ {
+		if i > 10 {
+			t.Fatalf("pagination tidak berhenti, sudah dapat %v", ids)
+		}
+		notes, err := st.Notes.List(context.Background(), userID, store.NoteFilter{}, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(notes) == 0 {
+			return ids
+		}
+		ids = append(ids, noteIDs(notes)...)
+
+		last := notes[len(notes)-1]
+		page.After = &store.Cursor{ID: last.ID, Title: last.Title, Time: last.CreatedAt}
+		if page.Sort == store.SortUpdatedAt {
+			page.After.Time = last.UpdatedAt
+		}
+	}
+}
+
+func noteIDs(notes []models.Note) []int {
+	var ids []int
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+	return ids
+}
+
+// sameIDs membandingkan dua daftar ID tanpa memperhatikan urutan
+func sameIDs(got, want []int) bool {
+	a := append([]int(nil), got...)
+	b := append([]int(nil), want...)
+	sort.Ints(a)
+	sort.Ints(b)
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
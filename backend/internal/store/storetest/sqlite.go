@@ -0,0 +1,126 @@
+// Package storetest berisi helper untuk test yang butuh repository di atas database
+// sungguhan. Hanya di-import dari file _test.go.
+package storetest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"notes-api/internal/database"
+	"notes-api/internal/store"
+	"notes-api/internal/store/sqlstore"
+	"sync/atomic"
+	"testing"
+
+	"modernc.org/sqlite"
+)
+
+// OpenSQLite membuat database SQLite :memory: yang sudah dimigrasi untuk satu test.
+// Database ditutup otomatis setelah test selesai.
+func OpenSQLite(t testing.TB) *store.Store {
+	t.Helper()
+	db, err := sql.Open("sqlite", database.SQLiteDSN(":memory:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return setupSQLite(t, db)
+}
+
+// OpenCountingSQLite sama seperti OpenSQLite, tapi setiap query yang dijalankan repository
+// dihitung oleh QueryCounter. Dipakai untuk memastikan jumlah query tidak bertambah
+// mengikuti jumlah data (N+1).
+func OpenCountingSQLite(t testing.TB) (*store.Store, *QueryCounter) {
+	t.Helper()
+	counter := &QueryCounter{}
+	db := sql.OpenDB(countingConnector{dsn: database.SQLiteDSN(":memory:"), counter: counter})
+	st := setupSQLite(t, db)
+	counter.Reset()
+	return st, counter
+}
+
+func setupSQLite(t testing.TB, db *sql.DB) *store.Store {
+	t.Helper()
+
+	// Setiap koneksi ke :memory: adalah database terpisah, jadi cukup satu koneksi
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	m, err := database.NewMigrator(db, database.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("migration gagal: %v", err)
+	}
+
+	st, err := sqlstore.New(db, database.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+// QueryCounter menghitung query dan exec yang dikirim ke database
+type QueryCounter struct {
+	n atomic.Int64
+}
+
+// Count mengembalikan jumlah query sejak Reset terakhir
+func (c *QueryCounter) Count() int {
+	return int(c.n.Load())
+}
+
+// Reset mengembalikan hitungan ke nol
+func (c *QueryCounter) Reset() {
+	c.n.Store(0)
+}
+
+// countingConnector membuka koneksi driver sqlite yang dibungkus countingConn
+type countingConnector struct {
+	dsn     string
+	counter *QueryCounter
+}
+
+func (c countingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Driver().Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn, counter: c.counter}, nil
+}
+
+func (c countingConnector) Driver() driver.Driver {
+	return &sqlite.Driver{}
+}
+
+// countingConn meneruskan semua pemanggilan ke koneksi sqlite dan menghitung setiap
+// QueryContext dan ExecContext. BEGIN/COMMIT tidak ikut dihitung.
+type countingConn struct {
+	driver.Conn
+	counter *QueryCounter
+}
+
+func (c *countingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.counter.n.Add(1)
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *countingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.counter.n.Add(1)
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c *countingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+}
+
+func (c *countingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *countingConn) CheckNamedValue(v *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(v)
+	}
+	return driver.ErrSkip
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"notes-api/internal/models"
+)
+
+// TagStore menyimpan tag milik user dan relasinya dengan catatan
+type TagStore interface {
+	// List mengambil semua tag milik user beserta jumlah catatan aktif yang memakainya
+	List(ctx context.Context, userID int) ([]models.Tag, error)
+
+	// Get mengambil satu tag, ErrNotFound jika bukan milik user
+	Get(ctx context.Context, userID, tagID int) (models.Tag, error)
+
+	// Create menyimpan tag baru, ErrDuplicate jika nama tag sudah ada
+	Create(ctx context.Context, tag *models.Tag) error
+
+	// Rename mengganti nama tag dan menaikkan version catatan yang memakainya.
+	// ErrNotFound jika tag tidak ada, ErrDuplicate jika nama baru sudah dipakai tag lain.
+	Rename(ctx context.Context, userID, tagID int, name string) error
+
+	// Merge memindahkan semua catatan dari tag sumber ke tag tujuan lalu menghapus tag sumber.
+	// ErrNotFound jika tag sumber tidak ada, ErrTagNotFound jika tag tujuan tidak ada.
+	// Return jumlah catatan yang sebelumnya memakai tag sumber, termasuk yang sudah punya
+	// tag tujuan.
+	Merge(ctx context.Context, userID, sourceID, targetID int) (int64, error)
+
+	// Delete menghapus tag beserta relasinya ke catatan
+	Delete(ctx context.Context, userID, tagID int) error
+
+	// Attach memasang tag ke catatan aktif. ErrNotFound jika catatan tidak ada,
+	// ErrTagNotFound jika tag tidak ada, ErrDuplicate jika tag sudah terpasang.
+	Attach(ctx context.Context, userID, noteID, tagID int) error
+
+	// Detach melepas tag dari catatan aktif. ErrNotFound jika catatan tidak ada,
+	// ErrTagNotFound jika tag tidak terpasang di catatan itu.
+	Detach(ctx context.Context, userID, noteID, tagID int) error
+
+	// ForNotes mengambil tag untuk banyak catatan sekaligus, dikelompokkan per note ID
+	ForNotes(ctx context.Context, userID int, noteIDs []int) (map[int][]models.Tag, error)
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"time"
+)
+
+// TokenStore menyimpan token sesi: refresh token, access token yang dicabut,
+// personal access token, challenge login 2FA dan state login SSO
+type TokenStore interface {
+	// CreateRefreshToken menyimpan refresh token baru (hanya hash-nya)
+	CreateRefreshToken(ctx context.Context, token RefreshToken) error
+
+	// RotateRefreshToken menandai refresh token sudah dipakai lalu menyimpan successor
+	// (TokenHash dan ExpiresAt) dengan user dan family yang sama dalam satu transaksi, lalu
+	// mengembalikan kondisi token lama sebelum dipakai. Token yang dicabut atau kedaluwarsa
+	// tidak diubah; token yang sudah pernah di-rotate membuat seluruh family-nya dicabut.
+	// Dalam kedua kasus itu successor tidak disimpan. ErrNotFound jika tidak ada.
+	RotateRefreshToken(ctx context.Context, tokenHash string, successor RefreshToken) (RefreshToken, error)
+
+	// RevokeRefreshFamily mencabut family refresh token milik user berdasarkan salah satu token-nya
+	RevokeRefreshFamily(ctx context.Context, userID int, tokenHash string) error
+
+	// RevokeAccessToken mencabut satu access token (jti) sampai expiresAt
+	RevokeAccessToken(ctx context.Context, jti string, userID int, expiresAt time.Time) error
+
+	// AccessTokenStatus mengecek apakah jti sudah dicabut dan mengambil tokens_valid_after
+	// milik user (nil jika belum pernah diset). ErrNotFound jika user tidak ada.
+	AccessTokenStatus(ctx context.Context, jti string, userID int) (bool, *time.Time, error)
+
+	// RevokeAllForUser mencabut semua access token dan refresh token user yang sudah di-issue
+	RevokeAllForUser(ctx context.Context, userID int) error
+
+	// ListAccessTokens mengambil personal access token milik user, terbaru dulu
+	ListAccessTokens(ctx context.Context, userID int) ([]models.PersonalAccessToken, error)
+
+	// CreateAccessToken menyimpan personal access token baru lalu mengisi ID dan CreatedAt
+	CreateAccessToken(ctx context.Context, userID int, token *models.PersonalAccessToken, tokenHash string) error
+
+	// UseAccessToken mencari personal access token yang masih berlaku, mencatat waktu
+	// pemakaiannya, lalu mengembalikan pemilik dan scope-nya. ErrNotFound jika tidak berlaku
+	// atau akun pemiliknya dinonaktifkan.
+	UseAccessToken(ctx context.Context, tokenHash string) (int, []string, error)
+
+	// DeleteAccessToken menghapus personal access token milik user
+	DeleteAccessToken(ctx context.Context, userID, tokenID int) error
+
+	// CreateLoginChallenge menyimpan challenge login 2FA; challenge user yang sudah kedaluwarsa dibuang
+	CreateLoginChallenge(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+
+	// LoginChallenge mengambil challenge login berdasarkan hash token-nya
+	LoginChallenge(ctx context.Context, tokenHash string) (LoginChallenge, error)
+
+	// AttemptLoginChallenge mencatat satu percobaan kode 2FA untuk challenge secara atomik.
+	// ErrNotFound jika challenge tidak ada atau sudah mencapai maxAttempts percobaan.
+	AttemptLoginChallenge(ctx context.Context, challengeID, maxAttempts int) error
+
+	// ConsumeLoginChallenge menghapus challenge yang sudah berhasil dipakai.
+	// ErrNotFound jika challenge sudah dipakai request lain.
+	ConsumeLoginChallenge(ctx context.Context, challengeID int) error
+
+	// CreateOIDCState menyimpan state login SSO; state yang sudah kedaluwarsa dibuang
+	CreateOIDCState(ctx context.Context, state OIDCState) error
+
+	// ConsumeOIDCState mengambil lalu menghapus state login SSO (sekali pakai).
+	// ErrNotFound jika tidak ada atau sudah dipakai request lain.
+	ConsumeOIDCState(ctx context.Context, stateHash string) (OIDCState, error)
+}
+
+// RefreshToken adalah satu refresh token dalam sebuah family (rantai rotasi dari satu login)
+type RefreshToken struct {
+	ID        int
+	UserID    int
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+// LoginChallenge adalah tahap pertama login untuk user dengan 2FA aktif
+type LoginChallenge struct {
+	ID        int
+	UserID    int
+	Attempts  int
+	ExpiresAt time.Time
+}
+
+// OIDCState menyimpan data login SSO yang sedang berjalan
+type OIDCState struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
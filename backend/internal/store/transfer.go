@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"notes-api/internal/models"
+	"strings"
+)
+
+// TransferStore memindahkan seluruh data satu user antar database, dipakai perintah
+// admin `notes-api export-user` dan `import-user`
+type TransferStore interface {
+	// Export mengambil akun beserta semua folder, tag, catatan (termasuk yang ada di trash)
+	// dan revisinya. ErrNotFound jika user tidak ada.
+	Export(ctx context.Context, userID int) (models.UserExport, error)
+
+	// Import membuat user baru dari hasil Export dalam satu transaksi lalu mengembalikan
+	// ID-nya. Data divalidasi dengan CheckExport; ErrDuplicate jika username atau email
+	// sudah dipakai.
+	Import(ctx context.Context, data models.UserExport) (int, error)
+}
+
+// CheckExport memvalidasi isi file export sebelum di-import: versi format, data akun,
+// ID yang unik, dan relasi folder/tag yang mengarah ke data di file yang sama.
+// Error yang dikembalikan membungkus ErrInvalidExport.
+func CheckExport(data models.UserExport) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidExport, fmt.Sprintf(format, args...))
+	}
+
+	if data.Version != models.UserExportVersion {
+		return invalid("versi %d tidak didukung (harus %d)", data.Version, models.UserExportVersion)
+	}
+	if strings.TrimSpace(data.User.Username) == "" || strings.TrimSpace(data.User.Email) == "" || data.User.PasswordHash == "" {
+		return invalid("username, email dan password_hash wajib diisi")
+	}
+	if data.User.RevisionRetention < 1 {
+		return invalid("revision_retention harus minimal 1")
+	}
+
+	folders := map[int]*int{}
+	for _, f := range data.Folders {
+		if _, ok := folders[f.ID]; ok {
+			return invalid("folder %d muncul lebih dari sekali", f.ID)
+		}
+		folders[f.ID] = f.ParentID
+	}
+	for id, parentID := range folders {
+		// Naik ke parent paling atas; lebih dari len(folders) langkah berarti ada siklus
+		for steps := 0; parentID != nil; steps++ {
+			next, ok := folders[*parentID]
+			if !ok {
+				return invalid("folder %d memakai parent %d yang tidak ada", id, *parentID)
+			}
+			if steps > len(folders) {
+				return invalid("parent folder %d membentuk siklus", id)
+			}
+			parentID = next
+		}
+	}
+
+	tags := map[int]bool{}
+	tagNames := map[string]bool{}
+	for _, t := range data.Tags {
+		name := strings.ToLower(t.Name)
+		if tags[t.ID] || tagNames[name] {
+			return invalid("tag %d (%s) muncul lebih dari sekali", t.ID, t.Name)
+		}
+		tags[t.ID] = true
+		tagNames[name] = true
+	}
+
+	notes := map[int]bool{}
+	for _, n := range data.Notes {
+		if notes[n.ID] {
+			return invalid("catatan %d muncul lebih dari sekali", n.ID)
+		}
+		notes[n.ID] = true
+
+		if n.FolderID != nil {
+			if _, ok := folders[*n.FolderID]; !ok {
+				return invalid("catatan %d memakai folder %d yang tidak ada", n.ID, *n.FolderID)
+			}
+		}
+
+		noteTags := map[int]bool{}
+		for _, tagID := range n.TagIDs {
+			if !tags[tagID] || noteTags[tagID] {
+				return invalid("catatan %d memakai tag %d yang tidak ada atau ganda", n.ID, tagID)
+			}
+			noteTags[tagID] = true
+		}
+
+		revisions := map[int]bool{}
+		for _, rev := range n.Revisions {
+			if revisions[rev.Revision] {
+				return invalid("revisi %d catatan %d muncul lebih dari sekali", rev.Revision, n.ID)
+			}
+			revisions[rev.Revision] = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"notes-api/internal/models"
+	"time"
+)
+
+// UserStore menyimpan akun user beserta data login-nya: password, verifikasi dan
+// perubahan email, reset password, 2FA dan identity dari login SSO
+type UserStore interface {
+	// Create menyimpan user baru (PasswordHash wajib diisi) lalu mengisi ID dan CreatedAt.
+	// ErrDuplicate jika username atau email sudah dipakai.
+	Create(ctx context.Context, user *models.User) error
+
+	// Get dan GetByEmail mengambil user termasuk PasswordHash, ErrNotFound jika tidak ada
+	Get(ctx context.Context, userID int) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+
+	// List mengambil semua user urut ID, dipakai perintah admin `notes-api user list`
+	List(ctx context.Context) ([]models.User, error)
+
+	// UsernameTaken dan EmailTaken mengecek apakah nilai sudah dipakai user selain exceptID
+	UsernameTaken(ctx context.Context, username string, exceptID int) (bool, error)
+	EmailTaken(ctx context.Context, email string, exceptID int) (bool, error)
+
+	// UpdateProfile mengganti username dan nama lengkap, ErrDuplicate jika username sudah dipakai
+	UpdateProfile(ctx context.Context, userID int, username, fullName string) error
+
+	// SetPassword mengganti password hash user
+	SetPassword(ctx context.Context, userID int, passwordHash string) error
+
+	// SetDisabled menonaktifkan atau mengaktifkan kembali akun, ErrNotFound jika user tidak
+	// ada. Token yang sudah terbit tidak ikut dicabut, panggil TokenStore.RevokeAllForUser.
+	SetDisabled(ctx context.Context, userID int, disabled bool) error
+
+	// CreateEmailVerification menyimpan hash token verifikasi email
+	CreateEmailVerification(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+
+	// VerifyEmail menandai email pemilik token terverifikasi lalu membuang semua token
+	// verifikasinya. ErrNotFound jika token tidak ada atau sudah kedaluwarsa.
+	VerifyEmail(ctx context.Context, tokenHash string) (int, error)
+
+	// RequestEmailChange menyimpan permintaan ganti email; permintaan lama user dibuang
+	RequestEmailChange(ctx context.Context, userID int, newEmail, tokenHash string, expiresAt time.Time) error
+
+	// ConfirmEmailChange memakai token (sekali pakai) untuk mengganti email user sekaligus
+	// menandainya terverifikasi, lalu mengembalikan email baru. ErrNotFound jika token
+	// tidak ada, ErrExpired jika sudah kedaluwarsa, ErrDuplicate jika email sudah dipakai.
+	ConfirmEmailChange(ctx context.Context, tokenHash string) (string, error)
+
+	// CreatePasswordReset menyimpan hash token reset password
+	CreatePasswordReset(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+
+	// ResetPassword mengganti password memakai token reset yang belum dipakai dan belum
+	// kedaluwarsa, lalu membuang token reset lain milik user. Return user ID pemilik token,
+	// ErrNotFound jika token tidak berlaku.
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (int, error)
+
+	// TwoFactor mengambil secret TOTP (kosong jika belum setup) dan status aktif 2FA
+	TwoFactor(ctx context.Context, userID int) (TwoFactor, error)
+
+	// SetTOTPSecret menyimpan secret TOTP baru yang belum aktif
+	SetTOTPSecret(ctx context.Context, userID int, secret string) error
+
+	// EnableTwoFactor mengaktifkan 2FA untuk secret yang sudah divalidasi, mencatat step
+	// kode pertama dan mengganti recovery code. ErrConflict jika 2FA sudah aktif atau
+	// secret sudah berganti sejak divalidasi.
+	EnableTwoFactor(ctx context.Context, userID int, secret string, step int64, recoveryHashes []string) error
+
+	// DisableTwoFactor mematikan 2FA dan membuang secret serta recovery code
+	DisableTwoFactor(ctx context.Context, userID int) error
+
+	// UseTOTPStep mencatat time step kode TOTP yang baru dipakai. false jika 2FA tidak aktif
+	// atau step tidak lebih baru dari step terakhir (kode yang sama dipakai ulang).
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+
+	// UseRecoveryCode menandai recovery code terpakai, false jika tidak ada atau sudah dipakai
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+
+	// ReplaceRecoveryCodes membuang recovery code lama dan menyimpan hash yang baru
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+
+	// FindIdentity mencari user yang terhubung ke akun identity provider, ErrNotFound jika belum ada
+	FindIdentity(ctx context.Context, issuer, subject string) (int, error)
+
+	// LinkIdentity menghubungkan akun identity provider ke user
+	LinkIdentity(ctx context.Context, userID int, issuer, subject, email string) error
+}
+
+// TwoFactor berisi status 2FA seorang user
+type TwoFactor struct {
+	Secret  string // kosong jika setup belum dijalankan
+	Enabled bool
+}
@@ -0,0 +1,46 @@
+package trash
+
+import (
+	"context"
+	"log"
+	"notes-api/internal/store"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultRetentionDays dipakai jika TRASH_RETENTION_DAYS tidak diset
+const DefaultRetentionDays = 30
+
+// RetentionDays membaca berapa hari item disimpan di trash dari env TRASH_RETENTION_DAYS
+func RetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		return DefaultRetentionDays
+	}
+	return days
+}
+
+// PurgeExpired menghapus permanen semua catatan dan folder yang sudah lebih dari days hari di trash
+func PurgeExpired(ctx context.Context, trash store.TrashStore, days int) (int64, error) {
+	cutoff := time.Now().UTC().Add(-time.Duration(days) * 24 * time.Hour)
+	return trash.PurgeBefore(ctx, cutoff)
+}
+
+// StartPurger menjalankan PurgeExpired di background setiap interval
+func StartPurger(trash store.TrashStore, days int, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			n, err := PurgeExpired(context.Background(), trash, days)
+			if err != nil {
+				log.Println("Gagal membersihkan trash:", err)
+			} else if n > 0 {
+				log.Printf("Trash dibersihkan: %d item dihapus permanen\n", n)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -2,7 +2,6 @@ package utils
 
 import (
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,25 +14,39 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-// GenerateToken membuat JWT token untuk user
+// GenerateToken membuat JWT access token untuk user
 func GenerateToken(userID int, email string) (string, error) {
-	// Token berlaku 24 jam
-	expirationTime := time.Now().Add(24 * time.Hour)
+	// Access token berumur pendek, diperpanjang lewat refresh token
+	now := time.Now()
+	expirationTime := now.Add(AccessTokenTTL())
+
+	// jti unik per token supaya token bisa dicabut satu per satu
+	jti, err := RandomID()
+	if err != nil {
+		return "", err
+	}
 
 	// Buat claims
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
 	}
 
-	// Buat token dengan signing method HS256
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if signingKey == nil {
+		return "", errors.New("kunci JWT belum dimuat")
+	}
+
+	// Buat token dengan signing method sesuai kunci aktif (RS256, EdDSA, atau HS256)
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	token.Header["kid"] = signingKey.ID
 
-	// Sign token dengan secret key
-	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	// Sign token dengan private key / secret
+	tokenString, err := token.SignedString(signingKey.Sign)
 	if err != nil {
 		return "", err
 	}
@@ -45,10 +58,8 @@ func GenerateToken(userID int, email string) (string, error) {
 func ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
-	// Parse token
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+	// Parse token, kunci dipilih berdasarkan header kid
+	token, err := jwt.ParseWithClaims(tokenString, claims, lookupVerificationKey)
 
 	if err != nil {
 		return nil, err
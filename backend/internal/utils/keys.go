@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Ukuran minimal kunci yang dianggap aman
+const (
+	minRSAKeyBits   = 2048
+	minHMACKeyBytes = 32
+)
+
+// legacyHMACKeyID adalah kid untuk token HS256 dari JWT_SECRET (token lama tidak punya kid)
+const legacyHMACKeyID = "hs256"
+
+// jwtKey adalah satu kunci untuk menandatangani atau memverifikasi JWT
+type jwtKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Sign   interface{} // private key / secret, nil jika hanya untuk verifikasi
+	Verify interface{} // public key / secret
+}
+
+// JWK adalah satu public key dalam format JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // Ed25519
+	X   string `json:"x,omitempty"`   // Ed25519 public key
+}
+
+// JWKSet adalah isi /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Kunci aktif, diisi oleh LoadKeys saat server start
+var (
+	signingKey       *jwtKey
+	verificationKeys = map[string]*jwtKey{}
+)
+
+// LoadKeys membaca kunci JWT dari env dan menolak start jika tidak ada kunci yang layak:
+//   - JWT_SIGNING_KEY (isi PEM) atau JWT_SIGNING_KEY_FILE: private key RSA (RS256) atau Ed25519 (EdDSA) untuk sign
+//   - JWT_VERIFICATION_KEY_FILES: daftar file PEM (dipisah koma) kunci lama yang masih diterima saat rotasi
+//   - JWT_SECRET: secret HS256 lama (minimal 32 byte); dipakai untuk sign hanya jika tidak ada private key,
+//     selain itu hanya untuk memverifikasi token lama sampai kedaluwarsa
+func LoadKeys() error {
+	signingKey = nil
+	verificationKeys = map[string]*jwtKey{}
+
+	pemData := []byte(os.Getenv("JWT_SIGNING_KEY"))
+	if file := os.Getenv("JWT_SIGNING_KEY_FILE"); file != "" && len(pemData) == 0 {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("gagal membaca JWT_SIGNING_KEY_FILE: %v", err)
+		}
+		pemData = data
+	}
+
+	if len(pemData) > 0 {
+		key, err := parsePEMKey(pemData)
+		if err != nil {
+			return fmt.Errorf("JWT signing key tidak valid: %v", err)
+		}
+		if key.Sign == nil {
+			return errors.New("JWT signing key harus berupa private key")
+		}
+		signingKey = key
+		verificationKeys[key.ID] = key
+	}
+
+	for _, file := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("gagal membaca verification key %s: %v", file, err)
+		}
+		key, err := parsePEMKey(data)
+		if err != nil {
+			return fmt.Errorf("verification key %s tidak valid: %v", file, err)
+		}
+		key.Sign = nil // kunci lama hanya untuk verifikasi
+		verificationKeys[key.ID] = key
+	}
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		if len(secret) < minHMACKeyBytes {
+			return fmt.Errorf("JWT_SECRET minimal %d karakter", minHMACKeyBytes)
+		}
+		key := &jwtKey{ID: legacyHMACKeyID, Method: jwt.SigningMethodHS256, Sign: []byte(secret), Verify: []byte(secret)}
+		verificationKeys[key.ID] = key
+		if signingKey == nil {
+			signingKey = key
+			log.Println("⚠️  JWT ditandatangani dengan HS256 (JWT_SECRET); set JWT_SIGNING_KEY_FILE untuk RS256/EdDSA")
+		}
+	}
+
+	if signingKey == nil {
+		return errors.New("tidak ada kunci JWT: set JWT_SIGNING_KEY_FILE, JWT_SIGNING_KEY, atau JWT_SECRET")
+	}
+
+	log.Printf("✅ JWT signing key %s (%s), %d verification key\n", signingKey.ID, signingKey.Method.Alg(), len(verificationKeys))
+	return nil
+}
+
+// PublicJWKS mengembalikan semua public key asimetris yang dipakai memverifikasi token.
+// Secret HS256 tentu tidak pernah dipublikasikan.
+func PublicJWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range verificationKeys {
+		if jwk, ok := toJWK(key); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// lookupVerificationKey dipakai ValidateToken untuk memilih kunci berdasarkan header kid dan alg
+func lookupVerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// Token yang di-issue sebelum ada kid selalu HS256
+		kid = legacyHMACKeyID
+	}
+
+	key, ok := verificationKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("kid %q tidak dikenal", kid)
+	}
+
+	// Algoritma harus sama dengan jenis kunci (mencegah serangan alg confusion)
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("algoritma %s tidak cocok dengan kunci %s", token.Method.Alg(), kid)
+	}
+
+	return key.Verify, nil
+}
+
+// parsePEMKey membaca private atau public key RSA/Ed25519 dari PEM
+func parsePEMKey(data []byte) (*jwtKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("bukan format PEM")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("tipe PEM %q tidak didukung", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &jwtKey{}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Sign, key.Verify = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.Verify = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Sign, key.Verify = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.Verify = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("jenis kunci %T tidak didukung (hanya RSA dan Ed25519)", parsed)
+	}
+
+	if pub, ok := key.Verify.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("kunci RSA minimal %d bit", minRSAKeyBits)
+	}
+
+	jwk, _ := toJWK(key)
+	key.ID = jwkThumbprint(jwk)
+	return key, nil
+}
+
+// toJWK mengubah public key menjadi JWK. ok false untuk kunci HMAC.
+func toJWK(key *jwtKey) (JWK, bool) {
+	b64 := base64.RawURLEncoding
+	switch pub := key.Verify.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA", Kid: key.ID, Use: "sig", Alg: key.Method.Alg(),
+			N: b64.EncodeToString(pub.N.Bytes()),
+			E: b64.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP", Kid: key.ID, Use: "sig", Alg: key.Method.Alg(),
+			Crv: "Ed25519",
+			X:   b64.EncodeToString(pub),
+		}, true
+	}
+	return JWK{}, false
+}
+
+// jwkThumbprint menghitung thumbprint RFC 7638 sebagai kid, sehingga kid sama di semua instance
+func jwkThumbprint(jwk JWK) string {
+	var canonical string
+	switch jwk.Kty {
+	case "RSA":
+		canonical = `{"e":"` + jwk.E + `","kty":"RSA","n":"` + jwk.N + `"}`
+	case "OKP":
+		canonical = `{"crv":"` + jwk.Crv + `","kty":"OKP","x":"` + jwk.X + `"}`
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
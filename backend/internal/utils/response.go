@@ -35,3 +35,21 @@ func WriteSuccess(w http.ResponseWriter, message string, data interface{}) {
 		Data:    data,
 	})
 }
+
+// PaginatedResponse untuk response list yang memakai cursor pagination
+type PaginatedResponse struct {
+	Success    bool        `json:"success"`
+	Message    string      `json:"message,omitempty"`
+	Data       interface{} `json:"data"`
+	NextCursor *string     `json:"next_cursor"` // null jika sudah halaman terakhir
+}
+
+// WritePaginated helper untuk menulis response list beserta cursor halaman berikutnya
+func WritePaginated(w http.ResponseWriter, message string, data interface{}, nextCursor *string) {
+	WriteJSON(w, http.StatusOK, PaginatedResponse{
+		Success:    true,
+		Message:    message,
+		Data:       data,
+		NextCursor: nextCursor,
+	})
+}
@@ -0,0 +1,5 @@
+-- FULLTEXT index untuk endpoint GET /api/search
+ALTER TABLE notes ADD FULLTEXT INDEX ft_notes_title_content (title, content);
+
+-- migrate:down
+ALTER TABLE notes DROP INDEX ft_notes_title_content;
@@ -0,0 +1,21 @@
+-- Riwayat revisi catatan: setiap update menyimpan isi catatan sebelum diubah
+
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    note_id INT NOT NULL,
+    user_id INT NOT NULL,
+    revision INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (note_id) REFERENCES notes(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_note_revision (note_id, revision)
+);
+
+-- Jumlah revisi maksimal yang disimpan per catatan (retention policy per user)
+ALTER TABLE users ADD COLUMN revision_retention INT NOT NULL DEFAULT 50;
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN revision_retention;
+DROP TABLE IF EXISTS note_revisions;
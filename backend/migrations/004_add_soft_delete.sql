@@ -0,0 +1,11 @@
+-- Soft delete: catatan dan folder yang dihapus masuk trash dulu sebelum dihapus permanen
+
+ALTER TABLE notes ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+ALTER TABLE notes ADD INDEX idx_notes_deleted_at (deleted_at);
+
+ALTER TABLE folders ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+ALTER TABLE folders ADD INDEX idx_folders_deleted_at (deleted_at);
+
+-- migrate:down
+ALTER TABLE folders DROP COLUMN deleted_at;
+ALTER TABLE notes DROP COLUMN deleted_at;
@@ -0,0 +1,8 @@
+-- Folder bertingkat: setiap folder bisa punya parent folder
+
+ALTER TABLE folders ADD COLUMN parent_id INT NULL DEFAULT NULL;
+ALTER TABLE folders ADD CONSTRAINT fk_folders_parent FOREIGN KEY (parent_id) REFERENCES folders(id) ON DELETE SET NULL;
+
+-- migrate:down
+ALTER TABLE folders DROP FOREIGN KEY fk_folders_parent;
+ALTER TABLE folders DROP COLUMN parent_id;
@@ -0,0 +1,5 @@
+-- Version counter untuk optimistic concurrency (ETag / If-Match)
+ALTER TABLE notes ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- migrate:down
+ALTER TABLE notes DROP COLUMN version;
@@ -0,0 +1,19 @@
+-- Refresh token disimpan dalam bentuk hash SHA-256, satu family per sesi login.
+-- Token yang sudah di-rotate lalu dipakai lagi menandakan pencurian: seluruh family dicabut.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    family_id CHAR(32) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP NULL DEFAULT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_token_hash (token_hash),
+    INDEX idx_refresh_tokens_family (family_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS refresh_tokens;
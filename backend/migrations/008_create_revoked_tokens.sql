@@ -0,0 +1,16 @@
+-- Revocation access token: jti yang dicabut (logout) dan batas waktu token per user (logout-all / ganti password)
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti CHAR(32) PRIMARY KEY,
+    user_id INT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_revoked_tokens_expires (expires_at)
+);
+
+-- Token yang di-issue sebelum waktu ini dianggap tidak valid
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMP(3) NULL DEFAULT NULL;
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN tokens_valid_after;
+DROP TABLE IF EXISTS revoked_tokens;
@@ -0,0 +1,15 @@
+-- Perubahan email menunggu konfirmasi lewat link yang dikirim ke alamat baru
+
+CREATE TABLE IF NOT EXISTS email_changes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    new_email VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_email_change_token (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS email_changes;
@@ -0,0 +1,20 @@
+-- Verifikasi email saat registrasi
+
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP NULL DEFAULT NULL;
+
+-- User yang sudah ada sebelum fitur ini dianggap sudah terverifikasi
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verifications (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_email_verification_token (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS email_verifications;
+ALTER TABLE users DROP COLUMN email_verified_at;
@@ -0,0 +1,36 @@
+-- Two-factor authentication (TOTP) dan recovery code
+
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64) NULL DEFAULT NULL,
+    ADD COLUMN totp_enabled_at TIMESTAMP NULL DEFAULT NULL,
+    ADD COLUMN totp_last_step BIGINT NULL DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_recovery_code (user_id, code_hash)
+);
+
+-- Challenge login tahap kedua (setelah password benar, sebelum kode 2FA)
+CREATE TABLE IF NOT EXISTS login_challenges (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_login_challenge_token (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS recovery_codes;
+ALTER TABLE users
+    DROP COLUMN totp_secret,
+    DROP COLUMN totp_enabled_at,
+    DROP COLUMN totp_last_step;
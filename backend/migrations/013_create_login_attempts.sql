@@ -0,0 +1,12 @@
+-- Hitungan login gagal per IP / akun (dipakai jika RATE_LIMIT_STORE=database)
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    rl_key VARCHAR(191) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure TIMESTAMP(3) NULL DEFAULT NULL,
+    blocked_until TIMESTAMP(3) NULL DEFAULT NULL,
+    INDEX idx_login_attempts_last_failure (last_failure)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,31 @@
+// Package migrations berisi file skema database untuk setiap driver. Semua file ikut
+// di-embed ke binary dan dijalankan oleh database.Migrator (tabel schema_migrations).
+//
+// Setiap file bernomor NNN_nama.sql berisi bagian up, lalu opsional bagian down setelah
+// baris "-- migrate:down" untuk rollback.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed *.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Folder migration per driver; migration MySQL ada di root folder ini
+var dirs = map[string]string{
+	"mysql":    ".",
+	"postgres": "postgres",
+	"sqlite":   "sqlite",
+}
+
+// For mengembalikan file migration untuk driver database (mysql, postgres, sqlite)
+func For(driver string) (fs.FS, error) {
+	dir, ok := dirs[driver]
+	if !ok {
+		return nil, fmt.Errorf("tidak ada migration untuk database %q", driver)
+	}
+	return fs.Sub(files, dir)
+}
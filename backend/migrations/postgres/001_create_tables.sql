@@ -0,0 +1,76 @@
+-- Database Schema untuk Aplikasi Catatan Pribadi (PostgreSQL)
+-- Total 5 tabel: users, folders, notes, tags, note_tags
+--
+-- Waktu disimpan sebagai TIMESTAMP tanpa zona waktu yang selalu berisi UTC. Kolom teks
+-- yang di MySQL tidak membedakan huruf besar/kecil (collation default) memakai CITEXT.
+
+CREATE EXTENSION IF NOT EXISTS citext;
+
+-- 1. Tabel users untuk autentikasi
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    username CITEXT NOT NULL UNIQUE CHECK (length(username) <= 50),
+    email CITEXT NOT NULL UNIQUE CHECK (length(email) <= 100),
+    password_hash VARCHAR(255) NOT NULL,
+    full_name VARCHAR(100),
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- 2. Tabel folders untuk kategorisasi catatan
+CREATE TABLE IF NOT EXISTS folders (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- 3. Tabel notes untuk menyimpan catatan
+CREATE TABLE IF NOT EXISTS notes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    folder_id INTEGER REFERENCES folders(id) ON DELETE SET NULL,
+    title CITEXT NOT NULL CHECK (length(title) <= 255),
+    content TEXT,
+    is_favorite BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    updated_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- Pengganti ON UPDATE CURRENT_TIMESTAMP di MySQL: updated_at diperbarui jika baris
+-- berubah dan query tidak mengisi updated_at sendiri
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW IS DISTINCT FROM OLD AND NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at THEN
+        NEW.updated_at := now() AT TIME ZONE 'UTC';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS notes_updated_at ON notes;
+CREATE TRIGGER notes_updated_at BEFORE UPDATE ON notes
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- 4. Tabel tags untuk label catatan
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name CITEXT NOT NULL CHECK (length(name) <= 50),
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_user_tag UNIQUE (user_id, name)
+);
+
+-- 5. Tabel note_tags untuk relasi many-to-many antara notes dan tags
+CREATE TABLE IF NOT EXISTS note_tags (
+    note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (note_id, tag_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS note_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS notes;
+DROP FUNCTION IF EXISTS set_updated_at();
+DROP TABLE IF EXISTS folders;
+DROP TABLE IF EXISTS users;
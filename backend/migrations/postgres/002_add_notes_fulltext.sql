@@ -0,0 +1,7 @@
+-- GIN index full-text untuk endpoint GET /api/search (pengganti FULLTEXT index MySQL).
+-- Ekspresinya harus sama dengan postgresSearchVector di sqlstore/dialect_postgres.go.
+CREATE INDEX IF NOT EXISTS idx_notes_search ON notes
+USING GIN (to_tsvector('simple', coalesce(title::text, '') || ' ' || coalesce(content, '')));
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_notes_search;
@@ -0,0 +1,19 @@
+-- Riwayat revisi catatan: setiap update menyimpan isi catatan sebelum diubah
+
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id SERIAL PRIMARY KEY,
+    note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_note_revision UNIQUE (note_id, revision)
+);
+
+-- Jumlah revisi maksimal yang disimpan per catatan (retention policy per user)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS revision_retention INTEGER NOT NULL DEFAULT 50;
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN revision_retention;
+DROP TABLE IF EXISTS note_revisions;
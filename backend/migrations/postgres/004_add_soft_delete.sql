@@ -0,0 +1,11 @@
+-- Soft delete: catatan dan folder yang dihapus masuk trash dulu sebelum dihapus permanen
+
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP(0) NULL DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes (deleted_at);
+
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP(0) NULL DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_folders_deleted_at ON folders (deleted_at);
+
+-- migrate:down
+ALTER TABLE folders DROP COLUMN deleted_at;
+ALTER TABLE notes DROP COLUMN deleted_at;
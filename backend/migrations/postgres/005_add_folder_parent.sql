@@ -0,0 +1,7 @@
+-- Folder bertingkat: setiap folder bisa punya parent folder
+
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS parent_id INTEGER NULL DEFAULT NULL
+    CONSTRAINT fk_folders_parent REFERENCES folders(id) ON DELETE SET NULL;
+
+-- migrate:down
+ALTER TABLE folders DROP COLUMN parent_id;
@@ -0,0 +1,19 @@
+-- Refresh token disimpan dalam bentuk hash SHA-256, satu family per sesi login.
+-- Token yang sudah di-rotate lalu dipakai lagi menandakan pencurian: seluruh family dicabut.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id CHAR(32) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP(0) NOT NULL,
+    rotated_at TIMESTAMP(0) NULL DEFAULT NULL,
+    revoked_at TIMESTAMP(0) NULL DEFAULT NULL,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens (family_id);
+
+-- migrate:down
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,14 @@
+-- Perubahan email menunggu konfirmasi lewat link yang dikirim ke alamat baru
+
+CREATE TABLE IF NOT EXISTS email_changes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP(0) NOT NULL,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_email_change_token UNIQUE (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS email_changes;
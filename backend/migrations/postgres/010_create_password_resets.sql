@@ -0,0 +1,14 @@
+-- Token reset password (hanya hash yang disimpan, sekali pakai)
+
+CREATE TABLE IF NOT EXISTS password_resets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP(0) NOT NULL,
+    used_at TIMESTAMP(0) NULL DEFAULT NULL,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_password_reset_token UNIQUE (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS password_resets;
@@ -0,0 +1,34 @@
+-- Two-factor authentication (TOTP) dan recovery code
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64) NULL DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP(0) NULL DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NULL DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP(0) NULL DEFAULT NULL,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_recovery_code UNIQUE (user_id, code_hash)
+);
+
+-- Challenge login tahap kedua (setelah password benar, sebelum kode 2FA)
+CREATE TABLE IF NOT EXISTS login_challenges (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP(0) NOT NULL,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_login_challenge_token UNIQUE (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS recovery_codes;
+ALTER TABLE users
+    DROP COLUMN totp_secret,
+    DROP COLUMN totp_enabled_at,
+    DROP COLUMN totp_last_step;
@@ -0,0 +1,13 @@
+-- Hitungan login gagal per IP / akun (dipakai jika RATE_LIMIT_STORE=database)
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    rl_key VARCHAR(191) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure TIMESTAMP(3) NULL DEFAULT NULL,
+    blocked_until TIMESTAMP(3) NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failure ON login_attempts (last_failure);
+
+-- migrate:down
+DROP TABLE IF EXISTS login_attempts;
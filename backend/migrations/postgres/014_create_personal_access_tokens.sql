@@ -0,0 +1,17 @@
+-- Personal access token untuk script dan integrasi (hanya hash yang disimpan)
+
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP(0) NULL DEFAULT NULL,
+    last_used_at TIMESTAMP(0) NULL DEFAULT NULL,
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_personal_access_token UNIQUE (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS personal_access_tokens;
@@ -0,0 +1,22 @@
+-- Login OIDC (SSO): akun eksternal yang terhubung ke user, dan state login yang sedang berjalan
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100),
+    created_at TIMESTAMP(0) DEFAULT (now() AT TIME ZONE 'UTC'),
+    CONSTRAINT unique_identity UNIQUE (issuer, subject)
+);
+
+CREATE TABLE IF NOT EXISTS oidc_states (
+    state_hash CHAR(64) PRIMARY KEY,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP(0) NOT NULL
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS oidc_states;
+DROP TABLE IF EXISTS user_identities;
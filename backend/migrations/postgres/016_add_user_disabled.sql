@@ -0,0 +1,6 @@
+-- Akun yang dinonaktifkan admin (notes-api user disable) tidak bisa login lagi
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP(0) NULL DEFAULT NULL;
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN disabled_at;
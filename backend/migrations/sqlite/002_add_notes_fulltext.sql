@@ -0,0 +1,32 @@
+-- Index FTS5 untuk endpoint GET /api/search (pengganti FULLTEXT index MySQL).
+-- notes_fts hanya menyimpan index, isinya dibaca dari tabel notes dan dijaga tetap
+-- sinkron oleh trigger di bawah.
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(
+    title,
+    content,
+    content='notes',
+    content_rowid='id',
+    tokenize='unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_insert AFTER INSERT ON notes BEGIN
+    INSERT INTO notes_fts (rowid, title, content) VALUES (NEW.id, NEW.title, NEW.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_delete AFTER DELETE ON notes BEGIN
+    INSERT INTO notes_fts (notes_fts, rowid, title, content) VALUES ('delete', OLD.id, OLD.title, OLD.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_update AFTER UPDATE OF title, content ON notes BEGIN
+    INSERT INTO notes_fts (notes_fts, rowid, title, content) VALUES ('delete', OLD.id, OLD.title, OLD.content);
+    INSERT INTO notes_fts (rowid, title, content) VALUES (NEW.id, NEW.title, NEW.content);
+END;
+
+-- Index catatan yang sudah ada sebelum migration ini
+INSERT INTO notes_fts (notes_fts) VALUES ('rebuild');
+
+-- migrate:down
+DROP TRIGGER IF EXISTS notes_fts_update;
+DROP TRIGGER IF EXISTS notes_fts_delete;
+DROP TRIGGER IF EXISTS notes_fts_insert;
+DROP TABLE IF EXISTS notes_fts;
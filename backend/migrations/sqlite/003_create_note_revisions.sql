@@ -0,0 +1,21 @@
+-- Riwayat revisi catatan: setiap update menyimpan isi catatan sebelum diubah
+
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    note_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    FOREIGN KEY (note_id) REFERENCES notes(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_note_revision UNIQUE (note_id, revision)
+);
+
+-- Jumlah revisi maksimal yang disimpan per catatan (retention policy per user)
+ALTER TABLE users ADD COLUMN revision_retention INTEGER NOT NULL DEFAULT 50;
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN revision_retention;
+DROP TABLE IF EXISTS note_revisions;
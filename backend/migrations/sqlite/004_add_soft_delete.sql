@@ -0,0 +1,13 @@
+-- Soft delete: catatan dan folder yang dihapus masuk trash dulu sebelum dihapus permanen
+
+ALTER TABLE notes ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes (deleted_at);
+
+ALTER TABLE folders ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_folders_deleted_at ON folders (deleted_at);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_folders_deleted_at;
+ALTER TABLE folders DROP COLUMN deleted_at;
+DROP INDEX IF EXISTS idx_notes_deleted_at;
+ALTER TABLE notes DROP COLUMN deleted_at;
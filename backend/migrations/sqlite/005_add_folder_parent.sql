@@ -0,0 +1,7 @@
+-- Folder bertingkat: setiap folder bisa punya parent folder
+-- (SQLite hanya bisa menambah foreign key lewat ADD COLUMN)
+
+ALTER TABLE folders ADD COLUMN parent_id INTEGER NULL DEFAULT NULL REFERENCES folders(id) ON DELETE SET NULL;
+
+-- migrate:down
+-- Tidak bisa di-rollback: SQLite tidak bisa menghapus kolom yang punya foreign key
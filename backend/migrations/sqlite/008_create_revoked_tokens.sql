@@ -0,0 +1,17 @@
+-- Revocation access token: jti yang dicabut (logout) dan batas waktu token per user (logout-all / ganti password)
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti CHAR(32) PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires ON revoked_tokens (expires_at);
+
+-- Token yang di-issue sebelum waktu ini dianggap tidak valid
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMP NULL DEFAULT NULL;
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN tokens_valid_after;
+DROP TABLE IF EXISTS revoked_tokens;
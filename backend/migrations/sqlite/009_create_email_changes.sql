@@ -0,0 +1,15 @@
+-- Perubahan email menunggu konfirmasi lewat link yang dikirim ke alamat baru
+
+CREATE TABLE IF NOT EXISTS email_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    new_email VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_email_change_token UNIQUE (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS email_changes;
@@ -0,0 +1,34 @@
+-- Two-factor authentication (TOTP) dan recovery code
+
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(64) NULL DEFAULT NULL;
+ALTER TABLE users ADD COLUMN totp_enabled_at TIMESTAMP NULL DEFAULT NULL;
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT NULL DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_recovery_code UNIQUE (user_id, code_hash)
+);
+
+-- Challenge login tahap kedua (setelah password benar, sebelum kode 2FA)
+CREATE TABLE IF NOT EXISTS login_challenges (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_login_challenge_token UNIQUE (token_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS recovery_codes;
+ALTER TABLE users DROP COLUMN totp_secret;
+ALTER TABLE users DROP COLUMN totp_enabled_at;
+ALTER TABLE users DROP COLUMN totp_last_step;
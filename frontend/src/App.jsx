@@ -6,6 +6,7 @@ import Header from './components/Layout/Header';
 import Loading from './components/UI/Loading';
 import Login from './pages/Login';
 import Register from './pages/Register';
+import OidcCallback from './pages/OidcCallback';
 import Dashboard from './pages/Dashboard';
 import Notes from './pages/Notes';
 import NoteForm from './components/Note/NoteForm';
@@ -43,6 +44,7 @@ const App = () => {
         <Routes>
           <Route path="/login" element={<Login />} />
           <Route path="/register" element={<Register />} />
+          <Route path="/oidc/callback" element={<OidcCallback />} />
           
           <Route path="/" element={
             <PrivateRoute>
@@ -12,6 +12,7 @@ export const authService = {
     const response = await api.post('/api/login', credentials);
     if (response.data.success && response.data.data.token) {
       localStorage.setItem('token', response.data.data.token);
+      localStorage.setItem('refresh_token', response.data.data.refresh_token);
       localStorage.setItem('user', JSON.stringify(response.data.data.user));
     }
     return response.data;
@@ -20,6 +21,7 @@ export const authService = {
   // Logout user
   logout: () => {
     localStorage.removeItem('token');
+    localStorage.removeItem('refresh_token');
     localStorage.removeItem('user');
   },
 
@@ -20,16 +20,58 @@ api.interceptors.request.use(
   }
 );
 
+// Refresh yang sedang berjalan, supaya request paralel yang kena 401 tidak me-refresh berkali-kali
+let refreshPromise = null;
+
+const clearSession = () => {
+  localStorage.removeItem('token');
+  localStorage.removeItem('refresh_token');
+  localStorage.removeItem('user');
+  window.location.href = '/login';
+};
+
+const refreshAccessToken = async () => {
+  const refreshToken = localStorage.getItem('refresh_token');
+  if (!refreshToken) {
+    throw new Error('Refresh token tidak ada');
+  }
+
+  // Pakai axios biasa supaya tidak melewati interceptor ini lagi
+  const response = await axios.post(
+    `${import.meta.env.VITE_API_URL}/api/token/refresh`,
+    { refresh_token: refreshToken }
+  );
+  const { token, refresh_token } = response.data.data;
+  localStorage.setItem('token', token);
+  localStorage.setItem('refresh_token', refresh_token);
+  return token;
+};
+
 api.interceptors.response.use(
   (response) => response,
-  (error) => {
+  async (error) => {
+    const original = error.config;
+
+    if (error.response?.status === 401 && original && !original._retry) {
+      original._retry = true;
+      try {
+        refreshPromise = refreshPromise || refreshAccessToken();
+        const token = await refreshPromise;
+        original.headers.Authorization = `Bearer ${token}`;
+        return api(original);
+      } catch (refreshError) {
+        clearSession();
+        return Promise.reject(refreshError);
+      } finally {
+        refreshPromise = null;
+      }
+    }
+
     if (error.response?.status === 401) {
-      localStorage.removeItem('token');
-      localStorage.removeItem('user');
-      window.location.href = '/login';
+      clearSession();
     }
     return Promise.reject(error);
   }
 );
 
-export default api;
\ No newline at end of file
+export default api;
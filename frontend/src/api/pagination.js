@@ -0,0 +1,33 @@
+import api from './axios';
+
+// Jumlah maksimal catatan per halaman yang diterima backend
+export const MAX_PAGE_LIMIT = 200;
+
+// fetchPage mengambil satu halaman dari endpoint list catatan (cursor pagination).
+// Return { items, nextCursor }; nextCursor null berarti sudah halaman terakhir.
+export const fetchPage = async (url, params = {}) => {
+  const response = await api.get(url, { params });
+  return {
+    items: response.data.data || [],
+    nextCursor: response.data.next_cursor || null,
+  };
+};
+
+// fetchAllPages mengikuti next_cursor sampai halaman terakhir, untuk tampilan yang butuh
+// seluruh catatan (jumlah catatan, isi folder/tag)
+export const fetchAllPages = async (url, params = {}) => {
+  const items = [];
+  let cursor = null;
+
+  do {
+    const page = await fetchPage(url, {
+      ...params,
+      limit: MAX_PAGE_LIMIT,
+      ...(cursor ? { cursor } : {}),
+    });
+    items.push(...page.items);
+    cursor = page.nextCursor;
+  } while (cursor);
+
+  return items;
+};
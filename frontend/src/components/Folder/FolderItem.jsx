@@ -4,6 +4,7 @@ import Modal from '../UI/Modal';
 import FolderForm from './FolderForm';
 import NoteItemInFolder from './NoteItemInFolder';
 import api from '../../api/axios';
+import { fetchAllPages } from '../../api/pagination';
 
 const FolderItem = ({ folder, onDelete, onUpdate, onToggleFavorite }) => {
   const [showEditModal, setShowEditModal] = useState(false);
@@ -15,9 +16,9 @@ const FolderItem = ({ folder, onDelete, onUpdate, onToggleFavorite }) => {
   useEffect(() => {
     const fetchNotes = async () => {
       try {
-        const response = await api.get(`/api/folders/${folder.id}/notes`);
-        setFolderNotes(response.data.data);
-        setNoteCount(response.data.data.length);
+        const notes = await fetchAllPages(`/api/folders/${folder.id}/notes`);
+        setFolderNotes(notes);
+        setNoteCount(notes.length);
       } catch (error) {
         console.error('Error fetching notes:', error);
       }
@@ -3,22 +3,35 @@ import { Search, Filter, Plus } from 'lucide-react';
 import NoteItem from './NoteItem';
 import Button from '../UI/Button';
 import Input from '../UI/Input';
-import api from '../../api/axios';
+import { fetchPage } from '../../api/pagination';
+
+// Jeda sebelum pencarian dikirim ke server setelah user berhenti mengetik
+const SEARCH_DELAY_MS = 300;
 
 const NoteList = ({ onAddClick }) => {
   const [notes, setNotes] = useState([]);
+  const [nextCursor, setNextCursor] = useState(null);
   const [loading, setLoading] = useState(true);
+  const [loadingMore, setLoadingMore] = useState(false);
   const [search, setSearch] = useState('');
   const [filter, setFilter] = useState('all');
 
+  // Filter dan pencarian dijalankan di server karena daftar catatan dibagi per halaman
+  const listParams = () => ({
+    ...(search ? { search } : {}),
+    ...(filter === 'favorite' ? { favorite: 'true' } : {}),
+  });
+
   useEffect(() => {
-    fetchNotes();
-  }, []);
+    const timer = setTimeout(fetchNotes, search ? SEARCH_DELAY_MS : 0);
+    return () => clearTimeout(timer);
+  }, [search, filter]);
 
   const fetchNotes = async () => {
     try {
-      const response = await api.get('/api/notes');
-      setNotes(response.data.data || []);
+      const page = await fetchPage('/api/notes', listParams());
+      setNotes(page.items);
+      setNextCursor(page.nextCursor);
     } catch (error) {
       console.error('Error fetching notes:', error);
     } finally {
@@ -26,6 +39,19 @@ const NoteList = ({ onAddClick }) => {
     }
   };
 
+  const handleLoadMore = async () => {
+    setLoadingMore(true);
+    try {
+      const page = await fetchPage('/api/notes', { ...listParams(), cursor: nextCursor });
+      setNotes(prev => [...prev, ...page.items]);
+      setNextCursor(page.nextCursor);
+    } catch (error) {
+      console.error('Error fetching notes:', error);
+    } finally {
+      setLoadingMore(false);
+    }
+  };
+
   const handleDelete = (noteId) => {
     setNotes(notes.filter(note => note.id !== noteId));
   };
@@ -38,16 +64,8 @@ const NoteList = ({ onAddClick }) => {
     ));
   };
 
-  const filteredNotes = notes.filter(note => {
-    const matchesSearch = search === '' || 
-      note.title.toLowerCase().includes(search.toLowerCase()) ||
-      note.content.toLowerCase().includes(search.toLowerCase());
-    
-    const matchesFilter = filter === 'all' || 
-      (filter === 'favorite' && note.is_favorite);
-    
-    return matchesSearch && matchesFilter;
-  });
+  // Catatan yang baru di-unfavorite langsung hilang dari filter favorit
+  const filteredNotes = notes.filter(note => filter === 'all' || note.is_favorite);
 
   if (loading) {
     return (
@@ -106,6 +124,13 @@ const NoteList = ({ onAddClick }) => {
               onToggleFavorite={handleToggleFavorite}
             />
           ))}
+          {nextCursor && (
+            <div className="flex justify-center">
+              <Button variant="secondary" onClick={handleLoadMore} disabled={loadingMore}>
+                {loadingMore ? 'Memuat...' : 'Muat lebih banyak'}
+              </Button>
+            </div>
+          )}
         </div>
       ) : (
         <div className="text-center py-12">
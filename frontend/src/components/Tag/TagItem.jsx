@@ -3,6 +3,7 @@ import { Tag, Trash2 } from 'lucide-react';
 import Modal from '../UI/Modal';
 import NoteItemInTag from './NoteItemInTag';
 import api from '../../api/axios';
+import { fetchAllPages } from '../../api/pagination';
 
 const TagItem = ({ tag, onDelete, onUpdate }) => {
   const [showEditModal, setShowEditModal] = useState(false);
@@ -29,8 +30,8 @@ const TagItem = ({ tag, onDelete, onUpdate }) => {
 
   const handleShowNotes = async () => {
     try {
-      const response = await api.get(`/api/tags/${tag.id}/notes`);
-      setTagNotes(response.data.data);
+      const notes = await fetchAllPages(`/api/tags/${tag.id}/notes`);
+      setTagNotes(notes);
       setShowNotesModal(true);
     } catch (error) {
       console.error('Error fetching notes:', error);
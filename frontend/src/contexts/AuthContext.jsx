@@ -1,4 +1,5 @@
 import React, { createContext, useState, useContext, useEffect } from 'react';
+import api from '../api/axios';
 
 const AuthContext = createContext({});
 
@@ -16,15 +17,31 @@ export const AuthProvider = ({ children }) => {
     setLoading(false);
   }, []);
 
-  const login = (userData, token) => {
+  const login = (userData, token, refreshToken) => {
     localStorage.setItem('user', JSON.stringify(userData));
     localStorage.setItem('token', token);
+    if (refreshToken) {
+      localStorage.setItem('refresh_token', refreshToken);
+    }
     setUser(userData);
   };
 
   const logout = () => {
+    // Cabut token di server (best effort), sesi lokal tetap dihapus walau request gagal
+    const token = localStorage.getItem('token');
+    const refreshToken = localStorage.getItem('refresh_token');
+    if (token) {
+      api
+        .post('/api/logout', { refresh_token: refreshToken }, {
+          headers: { Authorization: `Bearer ${token}` },
+          _retry: true, // jangan coba refresh jika token sudah kedaluwarsa
+        })
+        .catch(() => {});
+    }
+
     localStorage.removeItem('user');
     localStorage.removeItem('token');
+    localStorage.removeItem('refresh_token');
     setUser(null);
   };
 
@@ -7,7 +7,7 @@ import {
   TrendingUp
 } from 'lucide-react';
 import api from '../api/axios';
-import NoteList from '../components/Note/NoteList';
+import { fetchAllPages } from '../api/pagination';
 
 const Dashboard = () => {
   const [stats, setStats] = useState({
@@ -25,13 +25,13 @@ const Dashboard = () => {
 
   const fetchDashboardData = async () => {
     try {
-      const [notesRes, foldersRes, tagsRes] = await Promise.all([
-        api.get('/api/notes'),
+      // /api/notes hanya mengembalikan satu halaman, statistik butuh semua catatan
+      const [notes, foldersRes, tagsRes] = await Promise.all([
+        fetchAllPages('/api/notes'),
         api.get('/api/folders'),
         api.get('/api/tags'),
       ]);
 
-      const notes = notesRes.data.data || [];
       const favoriteNotes = notes.filter(note => note.is_favorite).length;
 
       setStats({
@@ -1,5 +1,5 @@
 import React, { useState } from 'react';
-import { Link, useNavigate } from 'react-router-dom';
+import { Link, useLocation, useNavigate } from 'react-router-dom';
 import { LogIn } from 'lucide-react';
 import Input from '../components/UI/Input';
 import Button from '../components/UI/Button';
@@ -13,6 +13,11 @@ const Login = () => {
   });
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
+  const location = useLocation();
+  // Diisi jika akun memakai 2FA: login dilanjutkan dengan kode dari aplikasi authenticator.
+  // Login SSO yang butuh 2FA diarahkan ke sini dengan challenge token di state.
+  const [challengeToken, setChallengeToken] = useState(location.state?.challengeToken || '');
+  const [code, setCode] = useState('');
   
   const navigate = useNavigate();
   const { login } = useAuth();
@@ -24,16 +29,35 @@ const Login = () => {
     });
   };
 
+  const handleSso = async () => {
+    setError('');
+    try {
+      // Cookie state dari authorize harus ikut terkirim ke callback
+      const response = await api.get('/api/oidc/authorize', { withCredentials: true });
+      window.location.assign(response.data.data.authorization_url);
+    } catch (err) {
+      setError(err.response?.data?.message || 'Login SSO gagal');
+    }
+  };
+
   const handleSubmit = async (e) => {
     e.preventDefault();
     setError('');
     setLoading(true);
 
     try {
-      const response = await api.post('/api/login', formData);
-      const { token, user } = response.data.data;
+      const response = challengeToken
+        ? await api.post('/api/login/2fa', { challenge_token: challengeToken, code })
+        : await api.post('/api/login', formData);
+
+      if (response.data.data.two_factor_required) {
+        setChallengeToken(response.data.data.challenge_token);
+        return;
+      }
+
+      const { token, refresh_token, user } = response.data.data;
       
-      login(user, token);
+      login(user, token, refresh_token);
       navigate('/dashboard');
     } catch (err) {
       setError(err.response?.data?.message || 'Login gagal');
@@ -55,6 +79,20 @@ const Login = () => {
         </div>
         
         <form className="mt-8 space-y-6" onSubmit={handleSubmit}>
+          {challengeToken ? (
+          <div className="rounded-md shadow-sm space-y-4">
+            <Input
+              label="Kode 2FA atau recovery code"
+              name="code"
+              type="text"
+              autoComplete="one-time-code"
+              required
+              value={code}
+              onChange={(e) => setCode(e.target.value)}
+              placeholder="123456"
+            />
+          </div>
+          ) : (
           <div className="rounded-md shadow-sm space-y-4">
             <Input
               label="Email"
@@ -76,6 +114,7 @@ const Login = () => {
               placeholder="••••••••"
             />
           </div>
+          )}
 
           {error && (
             <div className="text-red-600 text-sm text-center">{error}</div>
@@ -87,9 +126,22 @@ const Login = () => {
               className="w-full"
               disabled={loading}
             >
-              {loading ? 'Memproses...' : 'Masuk'}
+              {loading ? 'Memproses...' : challengeToken ? 'Verifikasi' : 'Masuk'}
             </Button>
           </div>
+
+          {import.meta.env.VITE_OIDC_ENABLED === 'true' && !challengeToken && (
+            <div>
+              <Button
+                type="button"
+                variant="secondary"
+                className="w-full"
+                onClick={handleSso}
+              >
+                Masuk dengan SSO
+              </Button>
+            </div>
+          )}
           
           <div className="text-center">
             <Link
@@ -0,0 +1,61 @@
+import React, { useEffect, useRef, useState } from 'react';
+import { Link, useNavigate, useSearchParams } from 'react-router-dom';
+import api from '../api/axios';
+import { useAuth } from '../contexts/AuthContext';
+import Loading from '../components/UI/Loading';
+
+// Halaman tujuan redirect identity provider setelah login SSO.
+// Code dan state diteruskan ke API untuk ditukar dengan token.
+const OidcCallback = () => {
+  const [searchParams] = useSearchParams();
+  const [error, setError] = useState('');
+  const navigate = useNavigate();
+  const { login } = useAuth();
+  // StrictMode menjalankan effect dua kali, padahal state hanya bisa dipakai sekali
+  const started = useRef(false);
+
+  useEffect(() => {
+    if (started.current) return;
+    started.current = true;
+
+    const code = searchParams.get('code');
+    const state = searchParams.get('state');
+    if (!code || !state) {
+      setError(searchParams.get('error_description') || 'Login SSO dibatalkan');
+      return;
+    }
+
+    api.post('/api/oidc/callback', { code, state }, { withCredentials: true })
+      .then((response) => {
+        // Akun dengan 2FA aktif melanjutkan ke langkah kode 2FA di halaman login
+        if (response.data.data.two_factor_required) {
+          navigate('/login', { replace: true, state: { challengeToken: response.data.data.challenge_token } });
+          return;
+        }
+
+        const { token, refresh_token, user } = response.data.data;
+        login(user, token, refresh_token);
+        navigate('/dashboard', { replace: true });
+      })
+      .catch((err) => {
+        setError(err.response?.data?.message || 'Login SSO gagal');
+      });
+  }, [searchParams, login, navigate]);
+
+  if (!error) {
+    return <Loading />;
+  }
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-50">
+      <div className="text-center">
+        <p className="text-red-600 mb-4">{error}</p>
+        <Link to="/login" className="text-blue-600 hover:text-blue-500 text-sm">
+          Kembali ke halaman login
+        </Link>
+      </div>
+    </div>
+  );
+};
+
+export default OidcCallback;
@@ -35,7 +35,7 @@ const Register = () => {
       setSuccess(true);
       setTimeout(() => {
         navigate('/login');
-      }, 2000);
+      }, 4000);
     } catch (err) {
       setError(err.response?.data?.message || 'Registrasi gagal');
     } finally {
@@ -52,7 +52,7 @@ const Register = () => {
             Registrasi Berhasil!
           </h2>
           <p className="text-gray-600">
-            Mengalihkan ke halaman login...
+            Cek email kamu untuk link verifikasi. Mengalihkan ke halaman login...
           </p>
         </div>
       </div>